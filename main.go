@@ -9,11 +9,43 @@ import (
 )
 
 func main() {
+	// --dir and --list choose where the todo files live, for both the TUI and subcommands
+	opts, args, err := model.ParseOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load the config before changing directory so a relative --config path works
+	if err := model.LoadConfig(model.ConfigPath(opts)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	dir, err := model.ResolveDataDir(opts)
+	if err == nil {
+		err = model.UseDataDir(dir, opts.List)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Any other arguments select a non-interactive subcommand instead of the TUI
+	if len(args) > 0 {
+		if err := model.RunCommand(args, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	model.UseTheme()
 	p := tea.NewProgram(
 		model.InitialModel(),
 		tea.WithAltScreen(), // Use alternate screen buffer to prevent scrolling
 	)
 	finalModel, err := p.Run()
+	model.ReleaseLock()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
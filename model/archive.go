@@ -0,0 +1,439 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archiveDatePattern matches the date in a backup file name
+var archiveDatePattern = regexp.MustCompile(`_(\d{4}-\d{2}-\d{2})_\d+\.txt$`)
+
+// archiveFile is a backup file made with B, as listed in the archive view
+type archiveFile struct {
+	name  string
+	date  string // Day the file was made, from its name; empty if the name has none
+	todos []Todo // In the order they're saved in the file
+}
+
+// loadArchiveFiles reads every backup file, newest first
+func loadArchiveFiles() []archiveFile {
+	names, err := findBackupFiles()
+	if err != nil {
+		return nil
+	}
+	archives := make([]archiveFile, 0, len(names))
+	for _, name := range names {
+		date := ""
+		if match := archiveDatePattern.FindStringSubmatch(name); match != nil {
+			date = match[1]
+		}
+		todos, _, err := readTodos(name)
+		if err != nil {
+			// Leave out archives that can't be read in full, so they're never rewritten
+			continue
+		}
+		archives = append(archives, archiveFile{name, date, todos})
+	}
+	sort.SliceStable(archives, func(i, j int) bool {
+		if archives[i].date != archives[j].date {
+			return archives[i].date > archives[j].date
+		}
+		return archives[i].name > archives[j].name
+	})
+	return archives
+}
+
+// completionSpan describes when an archive's todos were completed, e.g. "Jan 3 – Feb 28"
+func completionSpan(todos []Todo) string {
+	var first, last *Todo
+	for i := range todos {
+		at := todos[i].CompletedAt
+		if at == nil {
+			continue
+		}
+		if first == nil || at.Before(*first.CompletedAt) {
+			first = &todos[i]
+		}
+		if last == nil || at.After(*last.CompletedAt) {
+			last = &todos[i]
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	layout := "Jan 2"
+	if first.CompletedAt.Year() != last.CompletedAt.Year() {
+		layout = "Jan 2, 2006"
+	}
+	start, end := first.CompletedAt.Format(layout), last.CompletedAt.Format(layout)
+	if start == end {
+		return start
+	}
+	return start + " – " + end
+}
+
+// pluralTodos returns "1 todo" or "n todos"
+func pluralTodos(n int) string {
+	if n == 1 {
+		return "1 todo"
+	}
+	return fmt.Sprintf("%d todos", n)
+}
+
+// openArchives shows the archive view, listing the backup files made with B
+func (m *Model) openArchives() {
+	m.browsingArchives = true
+	m.readingArchive = false
+	m.archiveCursor = 0
+	m.archiveQuery = ""
+	m.archiveFiles = loadArchiveFiles()
+	m.showingUpdate = false
+	m.message = ""
+}
+
+// closeArchives leaves the archive view for the tabs
+func (m *Model) closeArchives() {
+	m.browsingArchives = false
+	m.readingArchive = false
+	m.archiveFiles = nil
+	m.archiveQuery = ""
+	m.showingUpdate = false
+	m.message = ""
+}
+
+// refreshArchives reads the backup files again after they've changed, keeping the
+// archive called selected open if it's still there and going back to the list if not
+func (m *Model) refreshArchives(selected string) {
+	m.archiveFiles = loadArchiveFiles()
+	for i, archive := range m.archiveFiles {
+		if archive.name == selected {
+			m.archiveCursor = i
+			m.archiveTodoCursor = max(0, min(m.archiveTodoCursor, len(m.archiveTodos())-1))
+			return
+		}
+	}
+	m.readingArchive = false
+	m.archiveQuery = ""
+	m.showingUpdate = false
+	m.archiveCursor = max(0, min(m.archiveCursor, len(m.archiveFiles)-1))
+}
+
+// selectedArchiveFile returns the archive selected in the list, which is the one being
+// read once it's opened
+func (m *Model) selectedArchiveFile() (archiveFile, bool) {
+	if m.archiveCursor >= len(m.archiveFiles) {
+		return archiveFile{}, false
+	}
+	return m.archiveFiles[m.archiveCursor], true
+}
+
+// archiveTodos returns the todos of the archive being read that match its search, most
+// recently completed first
+func (m *Model) archiveTodos() []Todo {
+	archive, ok := m.selectedArchiveFile()
+	if !ok || !m.readingArchive {
+		return nil
+	}
+	if strings.TrimSpace(m.archiveQuery) == "" {
+		return sortByCompletedDesc(archive.todos)
+	}
+	var matches []Todo
+	for _, todo := range archive.todos {
+		if todoMatchesQuery(todo, m.archiveQuery, true) {
+			matches = append(matches, todo)
+		}
+	}
+	return sortByCompletedDesc(matches)
+}
+
+// handleArchiveKey handles a key in the archive view, where the list of archives and
+// then the todos of the one opened are navigated like a tab
+func (m *Model) handleArchiveKey(key string) tea.Cmd {
+	rows, cursor := len(m.archiveFiles), &m.archiveCursor
+	if m.readingArchive {
+		rows, cursor = len(m.archiveTodos()), &m.archiveTodoCursor
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "j", "k", "g", "G":
+		switch key {
+		case "j":
+			*cursor = min(*cursor+1, max(0, rows-1))
+		case "k":
+			*cursor = max(*cursor-1, 0)
+		case "g":
+			*cursor = 0
+		case "G":
+			*cursor = max(0, rows-1)
+		}
+		m.showingUpdate = false
+		m.message = ""
+	case "enter", "l":
+		if !m.readingArchive && rows > 0 {
+			m.readingArchive = true
+			m.archiveTodoCursor = 0
+			m.message = ""
+		}
+	case "i":
+		if m.readingArchive && rows > 0 {
+			m.showingUpdate = !m.showingUpdate
+		}
+	case "/":
+		if m.readingArchive {
+			m.searchingArchive = true
+			m.archiveQuery = ""
+			m.archiveTodoCursor = 0
+			m.textInputCursor = 0
+			m.message = ""
+		}
+	case "r":
+		if m.readingArchive {
+			return m.restoreArchivedTodo()
+		}
+	case "M":
+		return m.mergeArchive()
+	case "ctrl+z", "U", "ctrl+r":
+		archive, _ := m.selectedArchiveFile()
+		var cmd tea.Cmd
+		if key == "ctrl+r" {
+			cmd = m.redo()
+		} else {
+			cmd = m.undo()
+		}
+		m.refreshArchives(archive.name)
+		return cmd
+	case "esc", "h":
+		switch {
+		case m.archiveQuery != "":
+			m.archiveQuery = ""
+			m.archiveTodoCursor = 0
+			m.message = "Search cleared"
+		case m.readingArchive:
+			m.readingArchive = false
+			m.showingUpdate = false
+			m.message = ""
+		default:
+			m.closeArchives()
+		}
+	case "O":
+		m.closeArchives()
+	}
+	return nil
+}
+
+// restoreArchivedTodo moves the selected todo of the archive being read to the end of
+// Ready, rewriting the archive without it
+func (m *Model) restoreArchivedTodo() tea.Cmd {
+	archive, _ := m.selectedArchiveFile()
+	todos := m.archiveTodos()
+	if m.archiveTodoCursor >= len(todos) {
+		return nil
+	}
+	todo := todos[m.archiveTodoCursor]
+	i := findTodoIndex(archive.todos, todo)
+	if i < 0 {
+		return nil
+	}
+	remaining := append(append([]Todo{}, archive.todos[:i]...), archive.todos[i+1:]...)
+
+	// Take the todo out of the archive first, so it's never in both places
+	redo := m.redoStack
+	m.pushUndo()
+	name, err := m.rewriteArchive(archive, remaining)
+	if err != nil {
+		// Put back anything rewritten and drop the undo entry, as nothing was moved
+		revertFileChanges(m.undoStack[len(m.undoStack)-1].files)
+		m.undoStack = m.undoStack[:len(m.undoStack)-1]
+		m.redoStack = redo
+		name = archive.name
+	}
+	m.forgetArchive()
+	m.updateDisplayedCompleted()
+	m.refreshArchives(name)
+	if err != nil {
+		m.message = "Failed to update the archive: " + err.Error()
+		return nil
+	}
+
+	todo.CompletedAt = nil
+	m.ready = append(m.ready, todo)
+	changes := m.undoStack[len(m.undoStack)-1].files
+	if cmd := m.save(readyFile, m.ready); cmd != nil {
+		// Put the archive back as it was, so the todo isn't lost
+		revertFileChanges(changes)
+		return cmd
+	}
+	m.message = fmt.Sprintf("Todo moved to %s: %s", strings.ToLower(viewName(viewReady)), todo.Text)
+	if len(remaining) == 0 {
+		m.message += fmt.Sprintf(" (it was the last one, so %s was removed)", archive.name)
+	}
+	return nil
+}
+
+// rewriteArchive saves todos as the archive's contents, renaming the file to match its
+// new count unless another file has that name, or removes it once it's empty. It
+// returns the archive's new name, recording the changes for undo.
+func (m *Model) rewriteArchive(archive archiveFile, todos []Todo) (string, error) {
+	if len(todos) == 0 {
+		if err := os.Remove(archive.name); err != nil {
+			return archive.name, err
+		}
+		m.recordFileChange(archive.name, archive.todos, nil)
+		return "", nil
+	}
+
+	name := archive.name
+	if archive.date != "" {
+		if renamed := archiveName(archive.date, len(todos)); renamed != name {
+			if _, err := os.Stat(renamed); errors.Is(err, os.ErrNotExist) {
+				name = renamed
+			}
+		}
+	}
+	if err := saveTodos(name, todos); err != nil {
+		return archive.name, err
+	}
+	if name == archive.name {
+		m.recordFileChange(name, archive.todos, todos)
+		return name, nil
+	}
+	m.recordFileChange(name, nil, todos)
+	if err := os.Remove(archive.name); err != nil {
+		return name, err
+	}
+	m.recordFileChange(archive.name, archive.todos, nil)
+	return name, nil
+}
+
+// mergeArchive moves every todo of the selected archive back into the Completed tab,
+// skipping any already there, and removes the archive
+func (m *Model) mergeArchive() tea.Cmd {
+	archive, ok := m.selectedArchiveFile()
+	if !ok {
+		return nil
+	}
+
+	m.pushUndo()
+	added := 0
+	for _, todo := range archive.todos {
+		if findTodoIndex(m.completed, todo) < 0 {
+			m.completed = append(m.completed, todo)
+			added++
+		}
+	}
+	if cmd := m.save(completedFile, m.completed); cmd != nil {
+		return cmd
+	}
+	if err := os.Remove(archive.name); err != nil && !os.IsNotExist(err) {
+		m.message = "Failed to remove the archive: " + err.Error()
+		return nil
+	}
+	m.recordFileChange(archive.name, archive.todos, nil)
+	m.forgetArchive()
+	m.updateDisplayedCompleted()
+	m.refreshArchives("")
+	m.message = fmt.Sprintf("Merged %s from %s into %s (ctrl+z to undo)", pluralTodos(added), archive.name, viewName(viewCompleted))
+	return nil
+}
+
+// renderArchiveView lists the backup files made with B with when they were made and
+// how many todos they hold, or shows the todos of the one being read
+func (m Model) renderArchiveView() string {
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	maxTextWidth := width - cfg.widthMargin
+
+	b := strings.Builder{}
+	b.WriteString("  " + activeTabStyle.Render("ARCHIVES") + "\n\n")
+
+	archive, ok := m.selectedArchiveFile()
+	if !ok {
+		b.WriteString("  " + infoMessageStyle.Render("No archives yet; B on the Completed tab archives its todos") + "\n\n")
+		b.WriteString("  " + helpTextStyle.Render("Press esc to exit the archive view") + "\n")
+		return b.String()
+	}
+
+	// Each row is an archive, or a todo of the one being read
+	var items []string
+	rows := Model{cursor: m.archiveCursor, width: m.width, height: m.height}
+	if m.readingArchive {
+		todos := m.archiveTodos()
+		b.WriteString("  " + headerStyle.Render(archive.name) + " " + countStyle.Render(pluralTodos(len(archive.todos))) + "\n\n")
+		if m.archiveQuery != "" && !m.searchingArchive {
+			b.WriteString("  " + headerStyle.Render("Search:") + " " + todoTextStyle.Render(m.archiveQuery) + " " +
+				helpTextStyle.Render(fmt.Sprintf("(%d of %d; esc to clear)", len(todos), len(archive.todos))) + "\n\n")
+		}
+
+		// Render the todos as the Completed tab would, with search matches highlighted
+		rows = Model{
+			completed:     todos,
+			currentView:   viewCompleted,
+			cursor:        m.archiveTodoCursor,
+			showingUpdate: m.showingUpdate,
+			searchActive:  m.archiveQuery != "",
+			searchQuery:   m.archiveQuery,
+			searchAll:     true,
+			width:         m.width,
+			height:        m.height,
+		}
+		for i, todo := range todos {
+			items = append(items, rows.renderTodo(i, todo, maxTextWidth))
+		}
+	} else {
+		for i, archive := range m.archiveFiles {
+			cursor := " "
+			if i == m.archiveCursor {
+				cursor = cursorStyle.Render(">")
+			}
+			date := archive.date
+			if date == "" {
+				date = "unknown date"
+			}
+			line := fmt.Sprintf("  %s %s  %s", cursor, todoTextStyle.Render(date), countStyle.Render(fmt.Sprintf("%9s", pluralTodos(len(archive.todos)))))
+			if span := completionSpan(archive.todos); span != "" {
+				line += "  " + timestampStyle.Render("completed "+span)
+			}
+			items = append(items, line+"  "+helpTextStyle.Render(archive.name)+"\n")
+		}
+	}
+
+	footer := strings.Builder{}
+	if m.searchingArchive {
+		wrappedLines := renderWrappedTextWithCursor(m.archiveQuery, m.textInputCursor, maxTextWidth+10)
+		footer.WriteString("  " + promptStyle.Render("Search:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			footer.WriteString("          " + wrappedLines[i] + "\n")
+		}
+		footer.WriteString("  " + helpTextStyle.Render("(searching text, updates and notes in this archive; Enter to confirm, Esc to cancel)") + "\n\n")
+	} else if m.readingArchive {
+		footer.WriteString("  " + commandStyle.Render("j/k: move  i: toggle details  /: search  r: move to ready  M: merge into completed  esc: back to archives") + "\n\n")
+	} else {
+		footer.WriteString("  " + commandStyle.Render("j/k: move  enter: open  M: merge into completed  ctrl+z/U: undo  esc: close") + "\n\n")
+	}
+	if m.fileWarning != "" {
+		footer.WriteString("  " + warningMessageStyle.Render(m.fileWarning) + "\n")
+	}
+	if m.message != "" {
+		footer.WriteString("  " + messageStyle(m.message).Render(m.message) + "\n")
+	}
+
+	if len(items) == 0 {
+		b.WriteString("  " + infoMessageStyle.Render("No matching todos") + "\n")
+	} else {
+		maxRows := m.height - strings.Count(b.String(), "\n") - strings.Count(footer.String(), "\n") - 1
+		b.WriteString(rows.windowList(items, maxRows))
+	}
+	b.WriteString("\n")
+	b.WriteString(footer.String())
+	return b.String()
+}
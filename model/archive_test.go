@@ -0,0 +1,200 @@
+package model
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadArchiveFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	at := func(month time.Month, day int) *time.Time {
+		completed := time.Date(2026, month, day, 9, 0, 0, 0, time.Local)
+		return &completed
+	}
+	saveTodos("todo_completed_backup_2026-02-01_2.txt", []Todo{
+		{ID: "1", Text: "Older", CompletedAt: at(time.January, 3)},
+		{ID: "2", Text: "Newer", CompletedAt: at(time.January, 28)},
+	})
+	saveTodos("todo_completed_backup_2026-03-01_1.txt", []Todo{{ID: "3", Text: "Latest", CompletedAt: at(time.February, 27)}})
+
+	archives := loadArchiveFiles()
+	var names []string
+	for _, archive := range archives {
+		names = append(names, archive.date)
+	}
+	if !reflect.DeepEqual(names, []string{"2026-03-01", "2026-02-01"}) {
+		t.Fatalf("loadArchiveFiles() dates = %q, want newest first", names)
+	}
+	if got := completionSpan(archives[1].todos); got != "Jan 3 – Jan 28" {
+		t.Errorf("completionSpan() = %q", got)
+	}
+	if got := completionSpan(archives[0].todos); got != "Feb 27" {
+		t.Errorf("completionSpan() = %q, want a single day", got)
+	}
+}
+
+func TestArchiveView(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	archived := []Todo{
+		{ID: "1", Text: "Pay invoice", CompletedAt: &now},
+		{ID: "2", Text: "Book dentist", CompletedAt: &now},
+		{ID: "3", Text: "Send invoice reminder", CompletedAt: &now},
+	}
+	archive := "todo_completed_backup_2026-01-05_3.txt"
+	saveTodos(archive, archived)
+
+	m := InitialModel()
+	press := func(keys ...string) {
+		t.Helper()
+		for _, key := range keys {
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			m = updated.(Model)
+		}
+	}
+
+	press("O")
+	if view := m.View(); !strings.Contains(view, "2026-01-05") || !strings.Contains(view, "3 todos") {
+		t.Errorf("View should list the archive with its date and count:\n%s", view)
+	}
+
+	// Search the open archive and move a match back to ready
+	press("enter", "/", "i", "n", "v", "enter")
+	if todos := m.archiveTodos(); len(todos) != 2 {
+		t.Fatalf("archiveTodos() = %+v, want the two invoices", todos)
+	}
+	if view := m.View(); !strings.Contains(view, "(2 of 3; esc to clear)") {
+		t.Errorf("View should show the search:\n%s", view)
+	}
+	m.archiveTodoCursor = findTodoIndex(m.archiveTodos(), archived[0])
+	press("r")
+	if len(m.ready) != 1 || m.ready[0].Text != "Pay invoice" || m.ready[0].CompletedAt != nil {
+		t.Fatalf("ready = %+v, want the todo moved back and no longer completed", m.ready)
+	}
+	renamed := "todo_completed_backup_2026-01-05_2.txt"
+	if _, err := os.Stat(archive); !os.IsNotExist(err) || len(loadTodos(renamed)) != 2 {
+		t.Errorf("the archive should be saved as %s without the todo", renamed)
+	}
+	if !m.readingArchive || len(m.archiveTodos()) != 1 {
+		t.Errorf("the renamed archive should stay open, showing %+v", m.archiveTodos())
+	}
+
+	press("ctrl+z")
+	if len(m.ready) != 0 || len(loadTodos(archive)) != 3 {
+		t.Errorf("undo should put the todo back in %s", archive)
+	}
+	if _, err := os.Stat(renamed); !os.IsNotExist(err) {
+		t.Errorf("undo should remove %s", renamed)
+	}
+
+	// Merge the whole archive into the Completed tab
+	press("M")
+	if len(m.completed) != 3 || !strings.HasPrefix(m.message, "Merged 3 todos from "+archive) {
+		t.Errorf("completed = %+v, message = %q, want every archived todo merged", m.completed, m.message)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Error("the merged archive should be removed")
+	}
+	if view := m.View(); !strings.Contains(view, "No archives yet") {
+		t.Errorf("View should show there are no archives left:\n%s", view)
+	}
+	press("U")
+	if len(m.completed) != 0 || len(m.archiveFiles) != 1 {
+		t.Errorf("undo should bring the archive back, completed = %+v", m.completed)
+	}
+
+	press("esc")
+	if m.browsingArchives {
+		t.Error("esc should close the archive view")
+	}
+}
+
+func TestArchiveViewReadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	archive := "todo_completed_backup_2026-01-05_1.txt"
+	saveTodos(archive, []Todo{{ID: "1", Text: "Archived"}})
+	writeLock(t, lockOwner{PID: 4242, Host: "other-machine", Started: time.Now()}, time.Now())
+
+	m := InitialModel()
+	for _, key := range []string{"O", "enter", "M", "r"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	if !m.readingArchive || !strings.HasPrefix(m.message, "Read-only") {
+		t.Errorf("archives should open but not change while read-only, message = %q", m.message)
+	}
+	if len(loadTodos(archive)) != 1 || len(m.ready) != 0 || len(m.completed) != 0 {
+		t.Error("nothing should be moved out of the archive while read-only")
+	}
+}
+
+func TestRestoreArchivedTodoSaveFails(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	archive := "todo_completed_backup_2026-01-05_1.txt"
+	saveTodos(archive, []Todo{{ID: "1", Text: "Archived"}})
+
+	m := InitialModel()
+	// A directory in its place makes saving the ready file fail
+	os.Mkdir(readyFile, 0755)
+
+	var cmd tea.Cmd
+	for _, key := range []string{"O", "enter", "r"} {
+		var updated tea.Model
+		updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	if cmd == nil || m.SaveError() == "" {
+		t.Fatal("a failed save should quit with the error")
+	}
+	if todos := loadTodos(archive); len(todos) != 1 || todos[0].Text != "Archived" {
+		t.Errorf("archive = %+v, want the todo kept when it couldn't be moved", todos)
+	}
+}
+
+func TestRestoreArchivedTodoRewriteFails(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	archive := "todo_completed_backup_2026-01-05_1.txt"
+	saveTodos(archive, []Todo{{ID: "1", Text: "Archived"}})
+
+	m := InitialModel()
+	for _, key := range []string{"O", "enter"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	// Removed behind the app's back, so removing it as emptied fails
+	os.Remove(archive)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	if !strings.HasPrefix(m.message, "Failed to update the archive") || len(m.ready) != 0 {
+		t.Fatalf("message = %q, ready = %+v, want the failure reported and nothing moved", m.message, m.ready)
+	}
+	if len(m.undoStack) != 0 {
+		t.Errorf("undoStack has %d entries, want none for a move that didn't happen", len(m.undoStack))
+	}
+}
@@ -0,0 +1,241 @@
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	backupDir = "backup"
+
+	// snapshotLayout names each snapshot's directory in backupDir after when it was taken
+	snapshotLayout = "2006-01-02_150405.000"
+)
+
+// backupSnapshot is a copy of every column's file taken when the app started, kept in
+// its own directory of backupDir
+type backupSnapshot struct {
+	name  string
+	taken time.Time
+}
+
+// dir returns the directory holding the snapshot's files
+func (s backupSnapshot) dir() string {
+	return filepath.Join(backupDir, s.name)
+}
+
+// listSnapshots returns the snapshots in backupDir, newest first
+func listSnapshots() ([]backupSnapshot, error) {
+	entries, err := os.ReadDir(backupDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []backupSnapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		taken, err := time.ParseInLocation(snapshotLayout, entry.Name(), time.Local)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, backupSnapshot{entry.Name(), taken})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].taken.After(snapshots[j].taken) })
+	return snapshots, nil
+}
+
+// readSnapshotFile returns the contents of filename in the snapshot, decompressing it
+// if it was saved with gzip, or false if the snapshot has no copy of it
+func readSnapshotFile(s backupSnapshot, filename string) ([]byte, bool, error) {
+	path := filepath.Join(s.dir(), filename)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return data, true, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+
+	compressed, err := os.ReadFile(path + ".gz")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s.gz: %w", path, err)
+	}
+	defer reader.Close()
+	data, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s.gz: %w", path, err)
+	}
+	return data, true, nil
+}
+
+// readSnapshotTodos returns the todos in the snapshot's copy of filename
+func readSnapshotTodos(s backupSnapshot, filename string) []Todo {
+	data, ok, err := readSnapshotFile(s, filename)
+	if !ok || err != nil {
+		return []Todo{}
+	}
+	todos, _, _ := parseTodos(bytes.NewReader(data), filename)
+	return todos
+}
+
+// writeSnapshotFile saves data as filename in dir, compressed with gzip if configured
+func writeSnapshotFile(dir, filename string, data []byte) error {
+	path := filepath.Join(dir, filename)
+	if !cfg.backupCompress {
+		return writeFileAtomic(path, data)
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path+".gz", buf.Bytes())
+}
+
+// createBackups takes a timestamped snapshot of every column's todo file in backupDir,
+// unless nothing has changed since the last one, then prunes snapshots the retention
+// policy no longer keeps
+func createBackups() error {
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return err
+	}
+	snapshots, err := listSnapshots()
+	if err != nil {
+		return err
+	}
+
+	// Read every column's file, noting whether any differs from the latest snapshot
+	files := map[string][]byte{}
+	changed := len(snapshots) == 0
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		data, err := os.ReadFile(filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("backing up %s: %w", filename, err)
+		}
+		files[filename] = data
+		if !changed {
+			previous, ok, err := readSnapshotFile(snapshots[0], filename)
+			changed = err != nil || !ok || !bytes.Equal(previous, data)
+		}
+	}
+
+	if changed && len(files) > 0 {
+		now := time.Now()
+		s := backupSnapshot{now.Format(snapshotLayout), now}
+		for {
+			// Never write into an earlier snapshot taken in the same millisecond
+			if _, err := os.Stat(s.dir()); errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			now = now.Add(time.Millisecond)
+			s = backupSnapshot{now.Format(snapshotLayout), now}
+		}
+		if err := os.MkdirAll(s.dir(), 0755); err != nil {
+			return err
+		}
+		for filename, data := range files {
+			if err := writeSnapshotFile(s.dir(), filename, data); err != nil {
+				return fmt.Errorf("backing up %s: %w", filename, err)
+			}
+		}
+		snapshots = append([]backupSnapshot{s}, snapshots...)
+	}
+
+	keep := snapshotsToKeep(snapshots, time.Now())
+	for _, s := range snapshots {
+		if !keep[s.name] {
+			if err := os.RemoveAll(s.dir()); err != nil {
+				return fmt.Errorf("removing old backup %s: %w", s.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotsToKeep applies the retention policy to snapshots, newest first: the latest
+// backupKeepLast, plus the newest of each of the last backupKeepDaily days and of each
+// of the last backupKeepWeekly weeks
+func snapshotsToKeep(snapshots []backupSnapshot, now time.Time) map[string]bool {
+	keep := map[string]bool{}
+	for i, s := range snapshots {
+		if i < cfg.backupKeepLast {
+			keep[s.name] = true
+		}
+	}
+
+	firstDay := truncateToDay(now).AddDate(0, 0, -cfg.backupKeepDaily)
+	firstWeek := getWeekStart(now).AddDate(0, 0, -7*cfg.backupKeepWeekly)
+	days, weeks := map[time.Time]bool{}, map[time.Time]bool{}
+	for _, s := range snapshots {
+		if day := truncateToDay(s.taken); day.After(firstDay) && !days[day] {
+			days[day] = true
+			keep[s.name] = true
+		}
+		if week := getWeekStart(s.taken); week.After(firstWeek) && !weeks[week] {
+			weeks[week] = true
+			keep[s.name] = true
+		}
+	}
+	return keep
+}
+
+// restoreBackup replaces every column's file with its copy in s, after backing up the
+// current files so the restore can itself be undone. A file the snapshot has no copy
+// of is emptied.
+func restoreBackup(s backupSnapshot) error {
+	// Read the snapshot first, as backing up the current files may prune it
+	files := map[string][]byte{}
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		data, ok, err := readSnapshotFile(s, filename)
+		if err != nil {
+			return err
+		}
+		if ok {
+			files[filename] = data
+		}
+	}
+
+	if err := createBackups(); err != nil {
+		return err
+	}
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		data, ok := files[filename]
+		if !ok {
+			if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+		}
+		if err := writeFileAtomic(filename, data); err != nil {
+			return fmt.Errorf("restoring %s: %w", filename, err)
+		}
+	}
+	return nil
+}
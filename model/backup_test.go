@@ -0,0 +1,164 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotsToKeep(t *testing.T) {
+	defer func() { cfg = defaultConfig() }()
+	cfg.backupKeepLast, cfg.backupKeepDaily, cfg.backupKeepWeekly = 2, 3, 2
+
+	// A Wednesday, with weeks starting on Sunday
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local)
+	at := func(day, hour int) backupSnapshot {
+		taken := time.Date(2026, 3, day, hour, 0, 0, 0, time.Local)
+		return backupSnapshot{taken.Format(snapshotLayout), taken}
+	}
+	snapshots := []backupSnapshot{
+		at(18, 11), // one of the last two
+		at(18, 10), // one of the last two
+		at(18, 9),  // older the same day
+		at(17, 20), // newest on the 17th
+		at(17, 8),  // older the same day
+		at(16, 8),  // newest on the 16th
+		at(15, 8),  // too old for daily, and this week already has a newer one
+		at(10, 8),  // newest of last week
+		at(2, 8),   // too old for weekly
+	}
+
+	keep := snapshotsToKeep(snapshots, now)
+	var kept []string
+	for _, s := range snapshots {
+		if keep[s.name] {
+			kept = append(kept, s.taken.Format("02 15h"))
+		}
+	}
+	expected := []string{"18 11h", "18 10h", "17 20h", "16 08h", "10 08h"}
+	if !reflect.DeepEqual(kept, expected) {
+		t.Errorf("snapshotsToKeep() kept %q, want %q", kept, expected)
+	}
+}
+
+func TestCreateBackupsPrunes(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	defer func() { cfg = defaultConfig() }()
+	cfg.backupKeepLast, cfg.backupKeepDaily, cfg.backupKeepWeekly = 2, 0, 0
+
+	for _, text := range []string{"First", "Second", "Third"} {
+		saveTodos(readyFile, []Todo{{ID: "1", Text: text}})
+		if err := createBackups(); err != nil {
+			t.Fatalf("createBackups() error = %v", err)
+		}
+	}
+
+	snapshots, _ := listSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
+	}
+	if got := readSnapshotTodos(snapshots[1], readyFile); len(got) != 1 || got[0].Text != "Second" {
+		t.Errorf("oldest kept snapshot = %+v, want the second", got)
+	}
+}
+
+func TestCreateBackupsCompressed(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	defer func() { cfg = defaultConfig() }()
+	cfg.backupCompress = true
+
+	todos := []Todo{{ID: "1", Text: "Squeezed"}}
+	saveTodos(readyFile, todos)
+	if err := createBackups(); err != nil {
+		t.Fatalf("createBackups() error = %v", err)
+	}
+
+	snapshots, _ := listSnapshots()
+	if len(snapshots) != 1 {
+		t.Fatalf("got %d snapshots, want 1", len(snapshots))
+	}
+	entries, _ := os.ReadDir(snapshots[0].dir())
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{readyFile + ".gz"}) {
+		t.Errorf("snapshot files = %q, want only the compressed ready file", names)
+	}
+	if got := readSnapshotTodos(snapshots[0], readyFile); !reflect.DeepEqual(got, todos) {
+		t.Errorf("readSnapshotTodos() = %+v, want %+v", got, todos)
+	}
+
+	// A compressed snapshot still counts as unchanged
+	createBackups()
+	if snapshots, _ := listSnapshots(); len(snapshots) != 1 {
+		t.Errorf("got %d snapshots, want 1 when nothing changed", len(snapshots))
+	}
+}
+
+func TestRunCommandRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	var out bytes.Buffer
+	RunCommand([]string{"restore"}, &out)
+	if !strings.HasPrefix(out.String(), "No backups yet") {
+		t.Errorf("output = %q, want no backups yet", out.String())
+	}
+
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Good"}})
+	createBackups()
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Bad edit"}})
+	saveTodos(backlogFile, []Todo{{ID: "2", Text: "Added later"}})
+	createBackups()
+
+	out.Reset()
+	if err := RunCommand([]string{"restore"}, &out); err != nil {
+		t.Fatalf("restore = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "(backlog 1, ready 1, completed 0)") || !strings.Contains(lines[1], "(backlog 0, ready 1, completed 0)") {
+		t.Errorf("output = %q, want both backups listed newest first", out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand([]string{"restore", "2"}, &out); err != nil {
+		t.Fatalf("restore 2 = %v", err)
+	}
+	if ready := loadTodos(readyFile); len(ready) != 1 || ready[0].Text != "Good" {
+		t.Errorf("ready = %+v, want the good version back", ready)
+	}
+	if backlog := loadTodos(backlogFile); len(backlog) != 0 {
+		t.Errorf("backlog = %+v, want it emptied as it wasn't in the backup", backlog)
+	}
+	if !strings.HasPrefix(out.String(), "Restored the backup from") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	// The replaced files were backed up, so the restore can be undone
+	snapshots, _ := listSnapshots()
+	if len(snapshots) != 2 || readSnapshotTodos(snapshots[0], readyFile)[0].Text != "Bad edit" {
+		t.Errorf("snapshots = %+v, want the replaced files as the latest backup", snapshots)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, lockFile)); !os.IsNotExist(err) {
+		t.Error("restore should release the lock when done")
+	}
+
+	if err := RunCommand([]string{"restore", "9"}, &out); err == nil || !strings.Contains(err.Error(), "1-2") {
+		t.Errorf("restore 9 = %v, want the valid range", err)
+	}
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minBoardColumnWidth    = 24                    // Narrowest board column before falling back to tabs
+	boardGap               = 2                     // Spaces between board columns
+	boardAnimationFrames   = 4                     // Frames a moved todo takes to cross one column
+	boardAnimationInterval = 30 * time.Millisecond // Time between animation frames
+)
+
+// boardAnimation is a moved todo sliding across the board from one column to another
+type boardAnimation struct {
+	text  string
+	from  int // Index of the column the todo left
+	to    int // Index of the column it arrived in
+	frame int // Frames shown so far
+}
+
+// boardAnimationMsg advances the board animation by a frame
+type boardAnimationMsg struct{}
+
+// active reports whether the animation still has frames to show
+func (a boardAnimation) active() bool {
+	return a.text != "" && a.frame < a.totalFrames()
+}
+
+// totalFrames returns how many frames the animation lasts
+func (a boardAnimation) totalFrames() int {
+	distance := a.to - a.from
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance * boardAnimationFrames
+}
+
+// boardAnimationTick schedules the next animation frame
+func boardAnimationTick() tea.Cmd {
+	return tea.Tick(boardAnimationInterval, func(time.Time) tea.Msg { return boardAnimationMsg{} })
+}
+
+// columnIndex returns the position of v's column on the board
+func columnIndex(v view) int {
+	for i, candidate := range columnOrder() {
+		if candidate == v {
+			return i
+		}
+	}
+	return 0
+}
+
+// boardFits reports whether the terminal is wide enough to show every column side by
+// side; otherwise the tabbed layout is used
+func (m Model) boardFits() bool {
+	n := len(columnOrder())
+	return m.width >= 2+n*minBoardColumnWidth+(n-1)*boardGap
+}
+
+// boardColumnWidth returns the width of each board column
+func (m Model) boardColumnWidth() int {
+	n := len(columnOrder())
+	return (m.width - 2 - (n-1)*boardGap) / n
+}
+
+// startBoardAnimation slides text from the column of one view to another when the
+// board is shown, returning the command for the first frame
+func (m *Model) startBoardAnimation(text string, from, to view) tea.Cmd {
+	if !m.showingBoard || !m.boardFits() {
+		return nil
+	}
+	m.animation = boardAnimation{text: text, from: columnIndex(from), to: columnIndex(to)}
+	return boardAnimationTick()
+}
+
+// advanceBoardAnimation shows the next frame, returning the command for the frame after
+// it or nil once the todo has arrived
+func (m *Model) advanceBoardAnimation() tea.Cmd {
+	if !m.animation.active() {
+		return nil
+	}
+	m.animation.frame++
+	if !m.animation.active() {
+		m.animation = boardAnimation{}
+		return nil
+	}
+	return boardAnimationTick()
+}
+
+// columnTodos returns the todos shown in the column of v, as getCurrentList would if v
+// were the current view
+func (m Model) columnTodos(v view) []Todo {
+	m.currentView = v
+	m.updateDisplayedCompleted()
+	return m.getCurrentList()
+}
+
+// renderBoard renders every column side by side, each windowed to maxRows lines, with a
+// lane above them for todos moving between columns
+func (m Model) renderBoard(maxRows int) string {
+	width := m.boardColumnWidth()
+
+	// Column title and the blank line below it
+	maxRows -= 2
+
+	columns := []string{}
+	for i, v := range columnOrder() {
+		if i > 0 {
+			columns = append(columns, strings.Repeat(" ", boardGap))
+		}
+		columns = append(columns, m.renderBoardColumn(v, width, maxRows))
+	}
+	board := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+
+	s := strings.Builder{}
+	s.WriteString(m.renderAnimationLane(width) + "\n")
+	for _, line := range strings.Split(board, "\n") {
+		s.WriteString("  " + line + "\n")
+	}
+	return s.String()
+}
+
+// renderBoardColumn renders the title and todos of the column of v
+func (m Model) renderBoardColumn(v view, width, maxRows int) string {
+	todos := m.columnTodos(v)
+	title := fmt.Sprintf("%s (%d)", strings.ToUpper(viewName(v)), len(todos))
+	if v == m.currentView {
+		title = activeTabStyle.Render(title)
+	} else {
+		title = inactiveTabStyle.Render(title)
+	}
+
+	cards := make([]string, len(todos))
+	for i, todo := range todos {
+		cards[i] = m.renderCard(todo, v == m.currentView && i == m.cursor, width)
+	}
+
+	content := "  " + infoMessageStyle.Render("No todos") + "\n"
+	if len(cards) > 0 {
+		// Only the focused column scrolls with the cursor; the others show their top
+		windowed := m
+		if v != m.currentView {
+			windowed.cursor = 0
+		}
+		content = windowed.windowList(cards, maxRows)
+	}
+
+	column := lipgloss.NewStyle().Width(width).MaxWidth(width)
+	return column.Render(title + "\n\n" + strings.TrimSuffix(content, "\n"))
+}
+
+// renderCard renders a todo compactly for a board column
+func (m Model) renderCard(todo Todo, selected bool, width int) string {
+	cursor := "  "
+	if selected {
+		cursor = cursorStyle.Render(">") + " "
+	}
+
+	prefix := ""
+	textWidth := width - 2
+	if todo.Priority != "" {
+		prefix = renderPriority(todo.Priority) + " "
+		textWidth -= len(todo.Priority) + 1
+	}
+
+	renderText := func(line string) string { return renderTextWithTags(line, todo.Tags) }
+	if m.isSearchHighlighted(todo) {
+		renderText = func(line string) string { return highlightQuery(line, m.searchQuery) }
+	}
+
+	s := strings.Builder{}
+	for i, line := range wrapText(todo.Text, textWidth) {
+		if i == 0 {
+			s.WriteString(cursor + prefix + renderText(line) + "\n")
+		} else {
+			s.WriteString("  " + renderText(line) + "\n")
+		}
+	}
+	if due := renderDueDate(todo, time.Now()); due != "" {
+		s.WriteString("  " + due + "\n")
+	}
+	return s.String()
+}
+
+// renderAnimationLane renders the moving todo at its current position between columns,
+// or an empty line when nothing is moving
+func (m Model) renderAnimationLane(width int) string {
+	a := m.animation
+	if !a.active() {
+		return ""
+	}
+	start := a.from * (width + boardGap)
+	end := a.to * (width + boardGap)
+	offset := start + (end-start)*(a.frame+1)/a.totalFrames()
+
+	text := a.text
+	if runes := []rune(text); len(runes) > width-2 {
+		text = string(runes[:width-3]) + "…"
+	}
+	return "  " + strings.Repeat(" ", offset) + searchMatchStyle.Render(text)
+}
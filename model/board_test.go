@@ -0,0 +1,123 @@
+package model
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBoardView(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		backlog:     []Todo{{ID: "1", Text: "Plan trip", CreatedAt: time.Now()}},
+		ready:       []Todo{{ID: "2", Text: "Book hotel", CreatedAt: time.Now()}, {ID: "3", Text: "Pack bags", CreatedAt: time.Now()}},
+		width:       120,
+		height:      30,
+	}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		return cmd
+	}
+
+	press("v")
+	if !m.showingBoard {
+		t.Fatal("'v' should turn the board on")
+	}
+	view := m.View()
+	for _, want := range []string{"BACKLOG (1)", "READY (2)", "COMPLETED (0)", "Plan trip", "Pack bags"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("board should show %q:\n%s", want, view)
+		}
+	}
+	// Every column shares the rows, so todos from different columns sit on one line
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "Plan trip") && !strings.Contains(line, "Book hotel") {
+			t.Errorf("columns should be side by side, got line %q", line)
+		}
+	}
+
+	// Switching columns keeps the row, as far as the column reaches
+	press("j")
+	press("h")
+	if m.currentView != viewBacklog || m.cursor != 0 {
+		t.Errorf("after h currentView = %v, cursor = %d, want backlog row 0", m.currentView, m.cursor)
+	}
+	press("l")
+	press("j")
+	if m.cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.cursor)
+	}
+	press("l")
+	press("h")
+	if m.currentView != viewReady || m.cursor != 0 {
+		t.Errorf("currentView = %v, cursor = %d, want ready row 0 after visiting an empty column", m.currentView, m.cursor)
+	}
+
+	// Narrow terminals fall back to tabs
+	m.width = 60
+	view = m.View()
+	if !strings.Contains(view, "needs a wider terminal") || strings.Contains(view, "READY (2)") {
+		t.Errorf("narrow board should fall back to tabs:\n%s", view)
+	}
+
+	press("v")
+	if m.showingBoard || strings.Contains(m.View(), "wider terminal") {
+		t.Error("'v' should turn the board off")
+	}
+}
+
+func TestBoardAnimation(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView:  viewBacklog,
+		backlog:      []Todo{{ID: "1", Text: "Plan trip"}},
+		showingBoard: true,
+		width:        120,
+	}
+
+	// Moving from the backlog slides the todo one column right
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	m = updated.(Model)
+	if cmd == nil || !m.animation.active() {
+		t.Fatal("moving a todo on the board should start an animation")
+	}
+	if m.animation.from != 0 || m.animation.to != 1 {
+		t.Errorf("animation from %d to %d, want 0 to 1", m.animation.from, m.animation.to)
+	}
+
+	frames := 0
+	for cmd != nil {
+		updated, cmd = m.Update(boardAnimationMsg{})
+		m = updated.(Model)
+		frames++
+		if frames > boardAnimationFrames {
+			t.Fatal("animation should stop after crossing one column")
+		}
+	}
+	if frames != boardAnimationFrames || m.animation.active() {
+		t.Errorf("animation ran %d frames, active = %v, want %d frames then done", frames, m.animation.active(), boardAnimationFrames)
+	}
+
+	// Without the board moves aren't animated
+	m.showingBoard = false
+	m.currentView = viewReady
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if cmd != nil || m.animation.active() || len(m.completed) != 1 {
+		t.Error("moves outside the board shouldn't animate")
+	}
+}
@@ -0,0 +1,271 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// checklistHelp is shown while moving through a checklist
+const checklistHelp = "Checklist mode (j/k to navigate, space/x to toggle, a to add, n to rename, J/K to reorder, d to delete, esc to exit)"
+
+// checklistProgress returns how many of the todo's checklist items are done, and how
+// many items there are
+func checklistProgress(todo Todo) (done, total int) {
+	for _, item := range todo.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(todo.Checklist)
+}
+
+// formatChecklistProgress returns progress such as "[3/5]", or "" without a checklist
+func formatChecklistProgress(todo Todo) string {
+	done, total := checklistProgress(todo)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%d/%d]", done, total)
+}
+
+// renderChecklistProgress renders the progress indicator, highlighted once every item
+// is done
+func renderChecklistProgress(todo Todo) string {
+	done, total := checklistProgress(todo)
+	if total == 0 {
+		return ""
+	}
+	if done == total {
+		return completeNoteStyle.Render(formatChecklistProgress(todo))
+	}
+	return countStyle.Render(formatChecklistProgress(todo))
+}
+
+// checklistBlocksCompletion returns an error if require_checklist is set and the todo
+// still has unfinished checklist items
+func checklistBlocksCompletion(todo Todo) error {
+	if !cfg.requireChecklist {
+		return nil
+	}
+	if done, total := checklistProgress(todo); done < total {
+		return fmt.Errorf("%d of %d checklist items still to do", total-done, total)
+	}
+	return nil
+}
+
+// selectedTodo returns the todo under the cursor
+func (m *Model) selectedTodo() (Todo, bool) {
+	list := m.getCurrentList()
+	if m.cursor < 0 || m.cursor >= len(list) {
+		return Todo{}, false
+	}
+	return list[m.cursor], true
+}
+
+// modifySelected applies change to the todo under the cursor and saves its list
+func (m *Model) modifySelected(change func(*Todo)) tea.Cmd {
+	if m.currentView == viewCompleted {
+		m.updateCompletedTodo(change)
+		return m.save(completedFile, m.completed)
+	}
+	idx := m.selectedIndex()
+	if idx < 0 {
+		return nil
+	}
+	list := *m.listFor(m.currentView)
+	change(&list[idx])
+	return m.save(columnOf(m.currentView).file, list)
+}
+
+// startChecklist moves into the selected todo's checklist, or prompts for its first
+// item if it has none
+func (m *Model) startChecklist() {
+	todo, ok := m.selectedTodo()
+	if !ok {
+		return
+	}
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+	m.checklistCursor = 0
+	if len(todo.Checklist) == 0 {
+		m.startChecklistInput(false)
+		return
+	}
+	m.navigatingChecklist = true
+	m.message = checklistHelp
+}
+
+// startChecklistInput opens the checklist prompt, either for a new item or to rename
+// the selected one
+func (m *Model) startChecklistInput(rename bool) {
+	m.editingChecklist = true
+	m.renamingChecklistItem = rename
+	m.newChecklistItem = ""
+	if todo, ok := m.selectedTodo(); ok && rename && m.checklistCursor < len(todo.Checklist) {
+		m.newChecklistItem = todo.Checklist[m.checklistCursor].Text
+	}
+	m.textInputCursor = len([]rune(m.newChecklistItem))
+	m.message = ""
+}
+
+// handleChecklistInput handles a key typed into the checklist prompt. New items go
+// after the selected one, and the checklist stays open so several can be added in a row.
+func (m *Model) handleChecklistInput(key string) tea.Cmd {
+	switch key {
+	case "enter":
+		text := strings.TrimSpace(m.newChecklistItem)
+		rename := m.renamingChecklistItem
+		m.editingChecklist = false
+		m.renamingChecklistItem = false
+		m.newChecklistItem = ""
+		if text == "" {
+			m.leaveEmptyChecklist()
+			return nil
+		}
+
+		todo, ok := m.selectedTodo()
+		if !ok {
+			return nil
+		}
+		at := m.checklistCursor
+		if !rename && len(todo.Checklist) > 0 {
+			at++
+		}
+		m.pushUndo()
+		cmd := m.modifySelected(func(t *Todo) {
+			if rename && at < len(t.Checklist) {
+				t.Checklist[at].Text = text
+				return
+			}
+			checklist := make([]ChecklistItem, 0, len(t.Checklist)+1)
+			checklist = append(checklist, t.Checklist[:at]...)
+			checklist = append(checklist, ChecklistItem{Text: text})
+			t.Checklist = append(checklist, t.Checklist[at:]...)
+		})
+		if cmd != nil {
+			return cmd
+		}
+		m.checklistCursor = at
+		m.navigatingChecklist = true
+		m.message = "Checklist item added!"
+		if rename {
+			m.message = "Checklist item renamed!"
+		}
+	case "esc":
+		m.editingChecklist = false
+		m.renamingChecklistItem = false
+		m.newChecklistItem = ""
+		m.leaveEmptyChecklist()
+		m.message = "Cancelled"
+	default:
+		handleTextInput(key, &m.newChecklistItem, &m.textInputCursor)
+	}
+	return nil
+}
+
+// leaveEmptyChecklist leaves checklist mode if the selected todo has no items to move
+// through
+func (m *Model) leaveEmptyChecklist() {
+	if todo, ok := m.selectedTodo(); !ok || len(todo.Checklist) == 0 {
+		m.navigatingChecklist = false
+		m.checklistCursor = 0
+	}
+}
+
+// handleChecklistKey handles a key pressed while moving through a checklist. Every key
+// stays within the checklist until it is left with esc.
+func (m *Model) handleChecklistKey(key string) tea.Cmd {
+	todo, ok := m.selectedTodo()
+	if !ok || key == "esc" || key == "q" {
+		m.navigatingChecklist = false
+		m.checklistCursor = 0
+		m.message = ""
+		return nil
+	}
+	n := len(todo.Checklist)
+	if m.checklistCursor >= n {
+		m.checklistCursor = n - 1
+	}
+	i := m.checklistCursor
+
+	switch key {
+	case "j":
+		if i < n-1 {
+			m.checklistCursor++
+		}
+		m.message = ""
+	case "k":
+		if i > 0 {
+			m.checklistCursor--
+		}
+		m.message = ""
+	case " ", "x":
+		m.pushUndo()
+		if cmd := m.modifySelected(func(t *Todo) { t.Checklist[i].Done = !t.Checklist[i].Done }); cmd != nil {
+			return cmd
+		}
+		m.message = ""
+		if done, total := checklistProgress(todo); !todo.Checklist[i].Done && done+1 == total {
+			m.message = "Checklist completed!"
+		}
+	case "a":
+		m.startChecklistInput(false)
+	case "n":
+		m.startChecklistInput(true)
+	case "J", "K":
+		j := i + 1
+		if key == "K" {
+			j = i - 1
+		}
+		if j < 0 || j >= n {
+			return nil
+		}
+		m.pushUndo()
+		if cmd := m.modifySelected(func(t *Todo) { t.Checklist[i], t.Checklist[j] = t.Checklist[j], t.Checklist[i] }); cmd != nil {
+			return cmd
+		}
+		m.checklistCursor = j
+		m.message = ""
+	case "d":
+		m.pushUndo()
+		cmd := m.modifySelected(func(t *Todo) {
+			t.Checklist = append(t.Checklist[:i:i], t.Checklist[i+1:]...)
+		})
+		if cmd != nil {
+			return cmd
+		}
+		if m.checklistCursor >= n-1 && m.checklistCursor > 0 {
+			m.checklistCursor--
+		}
+		m.leaveEmptyChecklist()
+		m.message = "Checklist item deleted (ctrl+z to undo)"
+	}
+	return nil
+}
+
+// renderChecklist renders the todo's checklist below it, marking the selected item
+// while the checklist is being navigated
+func (m Model) renderChecklist(todo Todo, selected bool, maxTextWidth int) string {
+	s := strings.Builder{}
+	for j, item := range todo.Checklist {
+		cursor := "  "
+		if selected && m.navigatingChecklist && j == m.checklistCursor {
+			cursor = cursorStyle.Render("►") + " "
+		}
+		box, style := "☐ ", todoTextStyle
+		if item.Done {
+			box, style = "☑ ", helpTextStyle
+		}
+		for k, line := range wrapText(item.Text, maxTextWidth-5) {
+			if k == 0 {
+				s.WriteString("     " + cursor + style.Render(box+line) + "\n")
+			} else {
+				s.WriteString("     " + "  " + style.Render("  "+line) + "\n")
+			}
+		}
+	}
+	return s.String()
+}
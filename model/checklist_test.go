@@ -0,0 +1,178 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChecklistProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		checklist []ChecklistItem
+		expected  string
+	}{
+		{"no checklist", nil, ""},
+		{"none done", []ChecklistItem{{Text: "a"}, {Text: "b"}}, "[0/2]"},
+		{"some done", []ChecklistItem{{Text: "a", Done: true}, {Text: "b"}, {Text: "c", Done: true}}, "[2/3]"},
+		{"all done", []ChecklistItem{{Text: "a", Done: true}}, "[1/1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatChecklistProgress(Todo{Checklist: tt.checklist}); got != tt.expected {
+				t.Errorf("formatChecklistProgress() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateChecklist(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		ready:       []Todo{{ID: "1", Text: "Release", CreatedAt: time.Now()}},
+	}
+	press := func(key string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	typeText := func(text string) {
+		t.Helper()
+		for _, r := range text {
+			press(string(r))
+		}
+	}
+
+	// 'C' on a todo without a checklist prompts for its first item
+	press("C")
+	if !m.editingChecklist {
+		t.Fatal("'C' should prompt for the first checklist item")
+	}
+	typeText("Tag")
+	press("enter")
+	if !m.navigatingChecklist || len(m.ready[0].Checklist) != 1 {
+		t.Fatalf("checklist = %v, navigating = %v, want one item and checklist mode", m.ready[0].Checklist, m.navigatingChecklist)
+	}
+
+	// 'a' adds after the selected item
+	press("a")
+	typeText("Publish")
+	press("enter")
+	press("k")
+	press("a")
+	typeText("Build")
+	press("enter")
+	texts := []string{}
+	for _, item := range m.ready[0].Checklist {
+		texts = append(texts, item.Text)
+	}
+	if strings.Join(texts, ",") != "Tag,Build,Publish" {
+		t.Fatalf("checklist = %v, want Tag,Build,Publish", texts)
+	}
+
+	// Space toggles the selected item and the progress shows beside the todo
+	press(" ")
+	if !m.ready[0].Checklist[1].Done {
+		t.Errorf("space should mark Build done")
+	}
+	if view := m.View(); !strings.Contains(view, "[1/3]") || !strings.Contains(view, "☑ Build") {
+		t.Errorf("View should show the progress and checklist:\n%s", view)
+	}
+
+	// J moves the item down, d deletes it
+	press("J")
+	if m.ready[0].Checklist[2].Text != "Build" || m.checklistCursor != 2 {
+		t.Errorf("after J checklist = %v, cursor = %d", m.ready[0].Checklist, m.checklistCursor)
+	}
+	press("d")
+	if len(m.ready[0].Checklist) != 2 || m.checklistCursor != 1 {
+		t.Errorf("after d checklist = %v, cursor = %d", m.ready[0].Checklist, m.checklistCursor)
+	}
+
+	// 'n' renames the selected item
+	press("n")
+	if m.newChecklistItem != "Publish" {
+		t.Fatalf("rename prompt = %q, want the item's text", m.newChecklistItem)
+	}
+	typeText(" docs")
+	press("enter")
+	if m.ready[0].Checklist[1].Text != "Publish docs" {
+		t.Errorf("renamed item = %q", m.ready[0].Checklist[1].Text)
+	}
+
+	press("esc")
+	if m.navigatingChecklist {
+		t.Error("esc should leave checklist mode")
+	}
+	saved := loadTodos(readyFile)
+	if len(saved) != 1 || len(saved[0].Checklist) != 2 || saved[0].Checklist[1].Text != "Publish docs" {
+		t.Errorf("saved = %+v, want the checklist saved", saved)
+	}
+
+	// Undo brings the deleted item back
+	press("U")
+	press("U")
+	if len(m.ready[0].Checklist) != 3 {
+		t.Errorf("after undo checklist = %v, want 3 items", m.ready[0].Checklist)
+	}
+}
+
+func TestRequireChecklist(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	defer func() { cfg = defaultConfig() }()
+
+	unfinished := Todo{ID: "1", Text: "Release", Checklist: []ChecklistItem{{Text: "Tag", Done: true}, {Text: "Publish"}}}
+	m := Model{currentView: viewReady, ready: []Todo{unfinished}}
+
+	// Completing is allowed by default
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if len(updated.(Model).completed) != 1 {
+		t.Fatal("todos with unfinished checklists should complete unless required")
+	}
+
+	cfg.requireChecklist = true
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	blocked := updated.(Model)
+	if len(blocked.completed) != 0 || len(blocked.ready) != 1 {
+		t.Fatal("require_checklist should stop the todo completing")
+	}
+	if !strings.Contains(blocked.message, "1 of 2 checklist items still to do") {
+		t.Errorf("message = %q", blocked.message)
+	}
+
+	saveTodos(readyFile, []Todo{unfinished})
+	var out bytes.Buffer
+	if err := RunCommand([]string{"done", "1"}, &out); err == nil || !strings.Contains(err.Error(), "still to do") {
+		t.Errorf("RunCommand(done) error = %v, want the checklist to block it", err)
+	}
+}
+
+func TestNextOccurrenceResetsChecklist(t *testing.T) {
+	todo := Todo{Text: "Water plants", Recurrence: "daily", Checklist: []ChecklistItem{{Text: "Ferns", Done: true}}}
+	next, ok := nextOccurrence(todo, time.Now())
+	if !ok || len(next.Checklist) != 1 || next.Checklist[0].Done {
+		t.Errorf("next checklist = %v, want the items unchecked", next.Checklist)
+	}
+}
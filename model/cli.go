@@ -0,0 +1,559 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cliUsage is printed by the help command and on invalid invocations
+const cliUsage = `Usage:
+  todo                                   Start the interactive UI
+  todo add <text> [--top] [--backlog] [--due <date>] [--priority <P0-P3>]
+                                         Add a todo to ready (or backlog)
+  todo list [backlog|ready|<column>|completed] [--ids]
+                                         List todos (default: ready)
+  todo done <n>                          Mark ready todo n as complete
+  todo update <n> <text> [--backlog|--completed]
+                                         Add an update to todo n (default list: ready)
+  todo move <n> <backlog|ready|<column>|completed> [--from <list>]
+                                         Move todo n to another list
+  todo merge                             Merge conflicted copies left by a sync tool
+                                         into the todo files
+  todo restore [n]                       List the startup backups, or restore backup n
+  todo help                              Show this help
+
+Global options (before or after the command):
+  --dir <path>                           Directory holding the todo files
+  --list <name>                          Use a named list, e.g. --list work
+  --config <path>                        Config file (default: $TODO_CONFIG, then
+                                         $XDG_CONFIG_HOME/todo/config.json)
+
+Without --dir, files are read from $TODO_DIR, then the current directory if it already
+has todo files, then $XDG_DATA_HOME/todo (~/.local/share/todo).
+
+Todos can be referred to by their number in 'todo list' or by their ID ('todo list --ids').
+Extra workflow columns from the config file are named in lower case with underscores,
+e.g. 'todo list in_progress' for an "In Progress" column.
+`
+
+// listFiles maps CLI list names to the files backing them. Workflow columns can also
+// be named by their slug (see listFile).
+var listFiles = map[string]string{
+	"backlog":   backlogFile,
+	"ready":     readyFile,
+	"completed": completedFile,
+}
+
+// listFile returns the file backing a CLI list name: backlog, ready, completed or the
+// slug of any workflow column, e.g. in_progress (or in-progress) for "In Progress"
+func listFile(listName string) (string, error) {
+	if filename, ok := listFiles[listName]; ok {
+		return filename, nil
+	}
+	names := []string{"backlog", "ready"}
+	for _, v := range columnOrder() {
+		col := columnOf(v)
+		if slug := columnSlug(listName); slug != "" && slug == columnSlug(col.name) {
+			return col.file, nil
+		}
+		if col.file != backlogFile && col.file != readyFile && col.file != completedFile {
+			names = append(names, columnSlug(col.name))
+		}
+	}
+	return "", fmt.Errorf("unknown list %q (expected %s or completed)", listName, strings.Join(names, ", "))
+}
+
+// cliArgs holds positional arguments and flags parsed from a subcommand's arguments
+type cliArgs struct {
+	positional []string
+	flags      map[string]string
+}
+
+// parseCLIArgs splits args into positional arguments and --flags. Flags listed in
+// valueFlags consume the following argument as their value, those in boolFlags are
+// booleans, and any other flag is an error.
+// Flags may appear anywhere, so `todo add "text" --top` and `todo add --top "text"` are equivalent.
+func parseCLIArgs(args []string, boolFlags []string, valueFlags ...string) (cliArgs, error) {
+	parsed := cliArgs{flags: map[string]string{}}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			parsed.positional = append(parsed.positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			parsed.positional = append(parsed.positional, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--")
+		value := "true"
+		hasValue := false
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name, value, hasValue = name[:eq], name[eq+1:], true
+		}
+		switch {
+		case slices.Contains(boolFlags, name):
+		case slices.Contains(valueFlags, name):
+			if !hasValue {
+				if i+1 >= len(args) {
+					return parsed, fmt.Errorf("flag --%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+		default:
+			return parsed, fmt.Errorf("unknown flag --%s\n\n%s", name, cliUsage)
+		}
+		parsed.flags[name] = value
+	}
+	return parsed, nil
+}
+
+// has reports whether a boolean flag was given
+func (a cliArgs) has(name string) bool {
+	_, ok := a.flags[name]
+	return ok
+}
+
+// RunCommand executes a non-interactive subcommand against the todo files, writing
+// any output to out. It returns an error for unknown commands or invalid arguments.
+func RunCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("no command given\n\n" + cliUsage)
+	}
+
+	command, rest := args[0], args[1:]
+	switch command {
+	case "add", "done", "update", "move", "mv", "merge":
+		// Commands that change the files honour the app's lock
+		if err := acquireLock(); err != nil {
+			return err
+		}
+		defer ReleaseLock()
+	}
+
+	switch command {
+	case "add":
+		return runAdd(rest, out)
+	case "list", "ls":
+		return runList(rest, out)
+	case "done":
+		return runDone(rest, out)
+	case "update":
+		return runUpdate(rest, out)
+	case "move", "mv":
+		return runMove(rest, out)
+	case "merge":
+		return runMerge(rest, out)
+	case "restore":
+		return runRestore(rest, out)
+	case "help", "-h", "--help":
+		fmt.Fprint(out, cliUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, cliUsage)
+	}
+}
+
+// runAdd appends (or prepends with --top) a new todo to ready or backlog
+func runAdd(args []string, out io.Writer) error {
+	parsed, err := parseCLIArgs(args, []string{"top", "backlog"}, "due", "priority")
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(strings.Join(parsed.positional, " "))
+	if text == "" {
+		return errors.New("add requires todo text")
+	}
+
+	listName := "ready"
+	if parsed.has("backlog") {
+		listName = "backlog"
+	}
+	filename := listFiles[listName]
+
+	todos, err := loadTodosWithIDs(filename)
+	if err != nil {
+		return err
+	}
+	newTodo := Todo{
+		ID:        newTodoID(),
+		Text:      formatTodoText(text),
+		Tags:      parseTags(text),
+		CreatedAt: time.Now(),
+	}
+	if input, ok := parsed.flags["due"]; ok {
+		due, err := parseDueDate(input, time.Now())
+		if err != nil {
+			return err
+		}
+		newTodo.DueAt = &due
+	}
+	if input, ok := parsed.flags["priority"]; ok {
+		level, err := parsePriority(input)
+		if err != nil {
+			return err
+		}
+		newTodo.Priority = level
+	}
+	if parsed.has("top") {
+		todos = append([]Todo{newTodo}, todos...)
+	} else {
+		todos = append(todos, newTodo)
+	}
+	if err := saveTodos(filename, todos); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Added to %s: %s\n", listName, newTodo.Text)
+	return nil
+}
+
+// runList prints the todos of a list with the 1-based numbers other commands accept
+func runList(args []string, out io.Writer) error {
+	parsed, err := parseCLIArgs(args, []string{"ids"})
+	if err != nil {
+		return err
+	}
+	listName := "ready"
+	if len(parsed.positional) > 0 {
+		listName = parsed.positional[0]
+	}
+	_, todos, err := loadCLIList(listName)
+	if err != nil {
+		return err
+	}
+	filename, _ := listFile(listName)
+	completed := filename == completedFile
+
+	if len(todos) == 0 {
+		fmt.Fprintf(out, "No todos in %s\n", listName)
+		return nil
+	}
+	for i, todo := range todos {
+		line := formatCLITodo(todo, completed)
+		if parsed.has("ids") {
+			line = todo.ID + "  " + line
+		}
+		fmt.Fprintf(out, "%3d. %s\n", i+1, line)
+	}
+	return nil
+}
+
+// runDone marks a ready todo as complete, mirroring the 'x' key in the UI
+func runDone(args []string, out io.Writer) error {
+	parsed, err := parseCLIArgs(args, nil)
+	if err != nil {
+		return err
+	}
+	if len(parsed.positional) != 1 {
+		return errors.New("done requires exactly one todo number")
+	}
+
+	ready, err := loadTodosWithIDs(readyFile)
+	if err != nil {
+		return err
+	}
+	idx, err := parseTodoRef(parsed.positional[0], ready)
+	if err != nil {
+		return err
+	}
+
+	todo := ready[idx]
+	if err := checklistBlocksCompletion(todo); err != nil {
+		return fmt.Errorf("can't complete %q yet: %w", todo.Text, err)
+	}
+	now := time.Now()
+	todo.CompletedAt = &now
+	stopTimer(&todo, now)
+	ready = append(ready[:idx], ready[idx+1:]...)
+	completed, err := loadTodosWithIDs(completedFile)
+	if err != nil {
+		return err
+	}
+	completed = append(completed, todo)
+
+	// Save where the todo goes before removing it from ready, so a failed save can't
+	// lose it
+	if err := saveTodos(completedFile, completed); err != nil {
+		return err
+	}
+
+	// Recurring todos come back with their next due date, as with 'x' in the UI
+	next, recurring := nextOccurrence(todo, now)
+	if recurring && belongsInReady(next, now) {
+		ready = append(ready, next)
+	} else if recurring {
+		backlog, err := loadTodosWithIDs(backlogFile)
+		if err != nil {
+			return err
+		}
+		if err := saveTodos(backlogFile, append([]Todo{next}, backlog...)); err != nil {
+			return err
+		}
+	}
+
+	if err := saveTodos(readyFile, ready); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Completed: %s\n", todo.Text)
+	if recurring {
+		fmt.Fprintf(out, "Next one %s\n", formatDueDate(*next.DueAt, now))
+	}
+	return nil
+}
+
+// runUpdate prepends an update to a todo, mirroring the 'u' key in the UI
+func runUpdate(args []string, out io.Writer) error {
+	parsed, err := parseCLIArgs(args, []string{"backlog", "completed"})
+	if err != nil {
+		return err
+	}
+	if len(parsed.positional) < 2 {
+		return errors.New("update requires a todo number and update text")
+	}
+	text := strings.TrimSpace(strings.Join(parsed.positional[1:], " "))
+	if text == "" {
+		return errors.New("update text cannot be empty")
+	}
+
+	listName := "ready"
+	if parsed.has("backlog") {
+		listName = "backlog"
+	} else if parsed.has("completed") {
+		listName = "completed"
+	}
+
+	todos, ordered, err := loadCLIList(listName)
+	if err != nil {
+		return err
+	}
+	idx, err := parseTodoRef(parsed.positional[0], ordered)
+	if err != nil {
+		return err
+	}
+
+	i := findTodoIndex(todos, ordered[idx])
+	todos[i].Updates = append([]TodoUpdate{newTodoUpdate(text, time.Now())}, todos[i].Updates...)
+	filename, _ := listFile(listName)
+	if err := saveTodos(filename, todos); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Update added to %s: %s\n", listName, todos[i].Text)
+	return nil
+}
+
+// runMerge merges the conflicted copies a sync tool left next to the todo files
+func runMerge(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		return errors.New("merge takes no arguments")
+	}
+	conflicts := findSyncConflicts()
+	if len(conflicts) == 0 {
+		fmt.Fprintln(out, "No conflicted copies found")
+		return nil
+	}
+	report, err := mergeSyncConflicts(conflicts)
+	for _, line := range report {
+		fmt.Fprintln(out, line)
+	}
+	return err
+}
+
+// runRestore lists the startup backups, newest first, or restores the numbered one
+func runRestore(args []string, out io.Writer) error {
+	snapshots, err := listSnapshots()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		if len(snapshots) == 0 {
+			fmt.Fprintln(out, "No backups yet; one is taken each time the app starts")
+			return nil
+		}
+		for i, s := range snapshots {
+			var counts []string
+			for _, v := range columnOrder() {
+				todos := readSnapshotTodos(s, columnOf(v).file)
+				counts = append(counts, fmt.Sprintf("%s %d", strings.ToLower(viewName(v)), len(todos)))
+			}
+			fmt.Fprintf(out, "%3d  %s  (%s)\n", i+1, s.taken.Format("Mon Jan 2, 2006 15:04:05"), strings.Join(counts, ", "))
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(snapshots) || len(args) > 1 {
+		return fmt.Errorf("restore takes a backup number from 'todo restore', 1-%d", len(snapshots))
+	}
+	if err := acquireLock(); err != nil {
+		return err
+	}
+	defer ReleaseLock()
+	s := snapshots[n-1]
+	if err := restoreBackup(s); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Restored the backup from %s; the files it replaced were backed up first\n", s.taken.Format("Mon Jan 2, 2006 15:04:05"))
+	return nil
+}
+
+// runMove moves a todo between lists. Without --from, moving to ready takes from
+// backlog and any other destination takes from ready.
+func runMove(args []string, out io.Writer) error {
+	parsed, err := parseCLIArgs(args, nil, "from")
+	if err != nil {
+		return err
+	}
+	if len(parsed.positional) != 2 {
+		return errors.New("move requires a todo number and a destination list")
+	}
+
+	to := parsed.positional[1]
+	toFile, err := listFile(to)
+	if err != nil {
+		return err
+	}
+	from := "ready"
+	if toFile == readyFile {
+		from = "backlog"
+	}
+	if value, ok := parsed.flags["from"]; ok {
+		from = value
+	}
+	fromFile, err := listFile(from)
+	if err != nil {
+		return err
+	}
+	if fromFile == toFile {
+		return fmt.Errorf("todo is already in %s", to)
+	}
+
+	source, ordered, err := loadCLIList(from)
+	if err != nil {
+		return err
+	}
+	idx, err := parseTodoRef(parsed.positional[0], ordered)
+	if err != nil {
+		return err
+	}
+
+	i := findTodoIndex(source, ordered[idx])
+	todo := source[i]
+	if toFile == completedFile {
+		if err := checklistBlocksCompletion(todo); err != nil {
+			return fmt.Errorf("can't complete %q yet: %w", todo.Text, err)
+		}
+	}
+	source = append(source[:i], source[i+1:]...)
+
+	now := time.Now()
+	if v, ok := fileView(toFile); ok && !timed(v) {
+		stopTimer(&todo, now)
+	}
+	switch toFile {
+	case completedFile:
+		todo.CompletedAt = &now
+	default:
+		todo.CompletedAt = nil
+	}
+
+	dest, err := loadTodosWithIDs(toFile)
+	if err != nil {
+		return err
+	}
+	if toFile == backlogFile {
+		// Matches the 'b' key, which puts todos at the top of the backlog
+		dest = append([]Todo{todo}, dest...)
+	} else {
+		dest = append(dest, todo)
+	}
+
+	// Save the destination first, so a failed save can't lose the todo
+	if err := saveTodos(toFile, dest); err != nil {
+		return err
+	}
+	if err := saveTodos(fromFile, source); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Moved to %s: %s\n", to, todo.Text)
+	return nil
+}
+
+// loadCLIList loads a list by name, returning both the stored order and the order it
+// is displayed in. Completed todos are shown most recently completed first, as in the
+// Completed tab; other lists are displayed as stored.
+func loadCLIList(listName string) (stored []Todo, ordered []Todo, err error) {
+	filename, err := listFile(listName)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err = loadTodosWithIDs(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	ordered = stored
+	if filename == completedFile {
+		ordered = sortByCompletedDesc(stored)
+	}
+	return stored, ordered, nil
+}
+
+// parseTodoRef converts a 1-based todo number or a todo ID into an index into todos
+func parseTodoRef(ref string, todos []Todo) (int, error) {
+	for i, todo := range todos {
+		if todo.ID != "" && todo.ID == ref {
+			return i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return 0, fmt.Errorf("no todo with number or ID %q", ref)
+	}
+	if n < 1 || n > len(todos) {
+		return 0, fmt.Errorf("todo number %d out of range (list has %d todos)", n, len(todos))
+	}
+	return n - 1, nil
+}
+
+// formatCLITodo renders a todo as a single line of plain text
+func formatCLITodo(todo Todo, completed bool) string {
+	line := todo.Text
+	if todo.Priority != "" {
+		line = "[" + todo.Priority + "] " + line
+	}
+	if todo.CompleteNote != "" {
+		line += " ✓"
+	}
+	if len(todo.Updates) > 0 {
+		line += fmt.Sprintf(" (%d updates)", len(todo.Updates))
+	}
+	if progress := formatChecklistProgress(todo); progress != "" {
+		line += " " + progress
+	}
+	if completed && todo.CompletedAt != nil {
+		line += " [" + todo.CompletedAt.Format("Jan 2, 15:04") + "]"
+	} else {
+		line += " [" + todo.CreatedAt.Format("Jan 2, 15:04") + "]"
+	}
+	if todo.DueAt != nil {
+		line += " [" + formatDueDate(*todo.DueAt, time.Now()) + "]"
+	}
+	if todo.Recurrence != "" {
+		line += " (repeats " + todo.Recurrence + ")"
+	}
+	if spent := timeSpent(todo, time.Now()); spent > 0 {
+		line += " (" + formatDuration(spent) + " logged)"
+	}
+	return line
+}
@@ -0,0 +1,384 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCLIArgs(t *testing.T) {
+	tests := []struct {
+		name               string
+		args               []string
+		expectedPositional []string
+		expectedFlags      map[string]string
+		expectError        bool
+	}{
+		{
+			name:               "positional only",
+			args:               []string{"Write report"},
+			expectedPositional: []string{"Write report"},
+			expectedFlags:      map[string]string{},
+		},
+		{
+			name:               "flags after positional",
+			args:               []string{"Write report", "--top", "--backlog"},
+			expectedPositional: []string{"Write report"},
+			expectedFlags:      map[string]string{"top": "true", "backlog": "true"},
+		},
+		{
+			name:               "value flag",
+			args:               []string{"1", "completed", "--from", "backlog"},
+			expectedPositional: []string{"1", "completed"},
+			expectedFlags:      map[string]string{"from": "backlog"},
+		},
+		{
+			name:               "value flag with equals",
+			args:               []string{"--from=backlog", "1", "ready"},
+			expectedPositional: []string{"1", "ready"},
+			expectedFlags:      map[string]string{"from": "backlog"},
+		},
+		{
+			name:               "double dash ends flags",
+			args:               []string{"--", "--not-a-flag"},
+			expectedPositional: []string{"--not-a-flag"},
+			expectedFlags:      map[string]string{},
+		},
+		{
+			name:        "missing flag value",
+			args:        []string{"1", "ready", "--from"},
+			expectError: true,
+		},
+		{
+			name:        "unknown flag",
+			args:        []string{"Write report", "--bakclog"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseCLIArgs(tt.args, []string{"top", "backlog"}, "from")
+			if tt.expectError {
+				if err == nil {
+					t.Error("parseCLIArgs() should return an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCLIArgs() error = %v", err)
+			}
+			if strings.Join(parsed.positional, "|") != strings.Join(tt.expectedPositional, "|") {
+				t.Errorf("positional = %v, want %v", parsed.positional, tt.expectedPositional)
+			}
+			if len(parsed.flags) != len(tt.expectedFlags) {
+				t.Errorf("flags = %v, want %v", parsed.flags, tt.expectedFlags)
+			}
+			for name, value := range tt.expectedFlags {
+				if parsed.flags[name] != value {
+					t.Errorf("flags[%q] = %q, want %q", name, parsed.flags[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCommandAdd(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(readyFile, []Todo{{Text: "Existing", CreatedAt: time.Now()}})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"add", "new task"}, &out); err != nil {
+		t.Fatalf("RunCommand(add) error = %v", err)
+	}
+	if err := RunCommand([]string{"add", "urgent task", "--top"}, &out); err != nil {
+		t.Fatalf("RunCommand(add --top) error = %v", err)
+	}
+	if err := RunCommand([]string{"add", "someday task", "--backlog"}, &out); err != nil {
+		t.Fatalf("RunCommand(add --backlog) error = %v", err)
+	}
+
+	ready := loadTodos(readyFile)
+	if len(ready) != 3 {
+		t.Fatalf("ready len = %d, want 3", len(ready))
+	}
+	if ready[0].Text != "Urgent task" {
+		t.Errorf("ready[0].Text = %q, want %q", ready[0].Text, "Urgent task")
+	}
+	if ready[2].Text != "New task" {
+		t.Errorf("ready[2].Text = %q, want %q", ready[2].Text, "New task")
+	}
+
+	backlog := loadTodos(backlogFile)
+	if len(backlog) != 1 || backlog[0].Text != "Someday task" {
+		t.Errorf("backlog = %v, want one todo 'Someday task'", backlog)
+	}
+
+	if !strings.Contains(out.String(), "Added to backlog: Someday task") {
+		t.Errorf("output = %q, want to contain add confirmation", out.String())
+	}
+
+	if err := RunCommand([]string{"add"}, &out); err == nil {
+		t.Error("RunCommand(add) without text should return an error")
+	}
+
+	// A mistyped flag is an error rather than quietly adding to ready
+	err := RunCommand([]string{"add", "typo task", "--bakclog"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "unknown flag --bakclog") {
+		t.Errorf("RunCommand(add --bakclog) error = %v, want an unknown flag error", err)
+	}
+	if len(loadTodos(readyFile)) != 3 || len(loadTodos(backlogFile)) != 1 {
+		t.Error("nothing should be added for an unknown flag")
+	}
+}
+
+func TestRunCommandList(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	older := now.Add(-2 * time.Hour)
+	newer := now.Add(-1 * time.Hour)
+	saveTodos(readyFile, []Todo{
+		{Text: "First", CreatedAt: now},
+		{Text: "Second", Updates: textUpdates("note"), CreatedAt: now},
+	})
+	saveTodos(completedFile, []Todo{
+		{Text: "Done earlier", CreatedAt: now, CompletedAt: &older},
+		{Text: "Done later", CreatedAt: now, CompletedAt: &newer},
+	})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"list"}, &out); err != nil {
+		t.Fatalf("RunCommand(list) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "1. First") || !strings.Contains(out.String(), "2. Second (1 updates)") {
+		t.Errorf("list output = %q, want numbered ready todos", out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand([]string{"list", "completed"}, &out); err != nil {
+		t.Fatalf("RunCommand(list completed) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "1. Done later") {
+		t.Errorf("completed list output = %q, want most recent first", out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand([]string{"list", "backlog"}, &out); err != nil {
+		t.Fatalf("RunCommand(list backlog) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No todos in backlog") {
+		t.Errorf("empty list output = %q", out.String())
+	}
+
+	if err := RunCommand([]string{"list", "someday"}, &out); err == nil {
+		t.Error("RunCommand(list someday) should return an error")
+	}
+}
+
+func TestRunCommandDone(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(readyFile, []Todo{
+		{Text: "task1", CreatedAt: time.Now()},
+		{Text: "task2", CreatedAt: time.Now()},
+	})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"done", "2"}, &out); err != nil {
+		t.Fatalf("RunCommand(done) error = %v", err)
+	}
+
+	ready := loadTodos(readyFile)
+	if len(ready) != 1 || ready[0].Text != "task1" {
+		t.Errorf("ready = %v, want only task1", ready)
+	}
+	completed := loadTodos(completedFile)
+	if len(completed) != 1 || completed[0].Text != "task2" {
+		t.Fatalf("completed = %v, want only task2", completed)
+	}
+	if completed[0].CompletedAt == nil {
+		t.Error("completed todo should have CompletedAt set")
+	}
+
+	for _, args := range [][]string{{"done"}, {"done", "0"}, {"done", "5"}, {"done", "abc"}} {
+		if err := RunCommand(args, &out); err == nil {
+			t.Errorf("RunCommand(%v) should return an error", args)
+		}
+	}
+}
+
+func TestRunCommandUpdate(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	older := now.Add(-2 * time.Hour)
+	newer := now.Add(-1 * time.Hour)
+	saveTodos(readyFile, []Todo{{Text: "task1", Updates: textUpdates("first"), CreatedAt: now}})
+	saveTodos(completedFile, []Todo{
+		{Text: "old", CreatedAt: now, CompletedAt: &older},
+		{Text: "recent", CreatedAt: now.Add(time.Minute), CompletedAt: &newer},
+	})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"update", "1", "second", "note"}, &out); err != nil {
+		t.Fatalf("RunCommand(update) error = %v", err)
+	}
+	ready := loadTodos(readyFile)
+	if len(ready[0].Updates) != 2 || ready[0].Updates[0].Text != "second note" {
+		t.Errorf("Updates = %v, want new update prepended", ready[0].Updates)
+	}
+
+	// Numbers for completed todos follow the most-recent-first display order
+	if err := RunCommand([]string{"update", "1", "shipped", "--completed"}, &out); err != nil {
+		t.Fatalf("RunCommand(update --completed) error = %v", err)
+	}
+	completed := loadTodos(completedFile)
+	if len(completed[1].Updates) != 1 || completed[1].Updates[0].Text != "shipped" {
+		t.Errorf("completed[1].Updates = %v, want [shipped]", completed[1].Updates)
+	}
+	if len(completed[0].Updates) != 0 {
+		t.Errorf("completed[0].Updates = %v, want none", completed[0].Updates)
+	}
+
+	if err := RunCommand([]string{"update", "1"}, &out); err == nil {
+		t.Error("RunCommand(update) without text should return an error")
+	}
+}
+
+func TestRunCommandMove(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	saveTodos(backlogFile, []Todo{{Text: "backlog task", CreatedAt: now}})
+	saveTodos(readyFile, []Todo{{Text: "ready task", CreatedAt: now, TimeLog: []TimeSession{{Start: now}}}})
+
+	var out bytes.Buffer
+
+	// Moving to ready defaults to taking from backlog
+	if err := RunCommand([]string{"move", "1", "ready"}, &out); err != nil {
+		t.Fatalf("RunCommand(move 1 ready) error = %v", err)
+	}
+	if len(loadTodos(backlogFile)) != 0 {
+		t.Error("backlog should be empty after move to ready")
+	}
+	ready := loadTodos(readyFile)
+	if len(ready) != 2 || ready[1].Text != "backlog task" {
+		t.Fatalf("ready = %v, want backlog task appended", ready)
+	}
+
+	// Moving to backlog defaults to taking from ready and prepends
+	if err := RunCommand([]string{"move", "1", "backlog"}, &out); err != nil {
+		t.Fatalf("RunCommand(move 1 backlog) error = %v", err)
+	}
+	backlog := loadTodos(backlogFile)
+	if len(backlog) != 1 || backlog[0].Text != "ready task" {
+		t.Errorf("backlog = %v, want ready task", backlog)
+	}
+	if timerRunning(backlog[0]) {
+		t.Error("moving to backlog should stop the todo's timer")
+	}
+
+	// Moving to completed stamps CompletedAt
+	if err := RunCommand([]string{"move", "1", "completed", "--from", "backlog"}, &out); err != nil {
+		t.Fatalf("RunCommand(move --from backlog) error = %v", err)
+	}
+	completed := loadTodos(completedFile)
+	if len(completed) != 1 || completed[0].CompletedAt == nil {
+		t.Fatalf("completed = %v, want one completed todo", completed)
+	}
+
+	// Moving out of completed clears CompletedAt
+	if err := RunCommand([]string{"move", "1", "ready", "--from", "completed"}, &out); err != nil {
+		t.Fatalf("RunCommand(move --from completed) error = %v", err)
+	}
+	ready = loadTodos(readyFile)
+	if ready[len(ready)-1].CompletedAt != nil {
+		t.Error("todo moved out of completed should have CompletedAt cleared")
+	}
+
+	for _, args := range [][]string{
+		{"move", "1"},
+		{"move", "1", "someday"},
+		{"move", "1", "ready", "--from", "ready"},
+	} {
+		if err := RunCommand(args, &out); err == nil {
+			t.Errorf("RunCommand(%v) should return an error", args)
+		}
+	}
+}
+
+func TestRunCommandTodoIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// Legacy plain-text lines are assigned persistent IDs on first use
+	os.WriteFile(readyFile, []byte("Same name\nSame name\n"), 0644)
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"list", "--ids"}, &out); err != nil {
+		t.Fatalf("RunCommand(list --ids) error = %v", err)
+	}
+	ready := loadTodos(readyFile)
+	if len(ready) != 2 || ready[0].ID == "" || ready[0].ID == ready[1].ID {
+		t.Fatalf("ready = %v, want two todos with distinct IDs", ready)
+	}
+	if !strings.Contains(out.String(), ready[1].ID) {
+		t.Errorf("list output = %q, want to contain ID %q", out.String(), ready[1].ID)
+	}
+
+	if err := RunCommand([]string{"done", ready[1].ID}, &out); err != nil {
+		t.Fatalf("RunCommand(done <id>) error = %v", err)
+	}
+	completed := loadTodos(completedFile)
+	if len(completed) != 1 || completed[0].ID != ready[1].ID {
+		t.Errorf("completed = %v, want todo with ID %q", completed, ready[1].ID)
+	}
+	remaining := loadTodos(readyFile)
+	if len(remaining) != 1 || remaining[0].ID != ready[0].ID {
+		t.Errorf("ready = %v, want todo with ID %q", remaining, ready[0].ID)
+	}
+
+	if err := RunCommand([]string{"done", "no-such-id"}, &out); err == nil {
+		t.Error("RunCommand(done) with unknown ID should return an error")
+	}
+}
+
+func TestRunCommandUnknown(t *testing.T) {
+	var out bytes.Buffer
+	err := RunCommand([]string{"frobnicate"}, &out)
+	if err == nil {
+		t.Fatal("RunCommand() with unknown command should return an error")
+	}
+	if !strings.Contains(err.Error(), "Usage:") {
+		t.Errorf("error = %q, want to include usage", err.Error())
+	}
+
+	out.Reset()
+	if err := RunCommand([]string{"help"}, &out); err != nil {
+		t.Fatalf("RunCommand(help) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "todo add") {
+		t.Errorf("help output = %q, want usage", out.String())
+	}
+}
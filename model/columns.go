@@ -0,0 +1,274 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// column is a workflow column, shown as a tab and saved in its own file
+type column struct {
+	name string
+	file string
+}
+
+// defaultColumns are the original Backlog, Ready and Completed columns
+func defaultColumns() []column {
+	return []column{{"Backlog", backlogFile}, {"Ready", readyFile}, {"Completed", completedFile}}
+}
+
+// parseColumns builds workflow columns from their names. The first column is the
+// backlog, the second is ready and the last is the done column, which stamps
+// CompletedAt; they keep the original files so existing todos carry over. Columns in
+// between are stages saved in todo_<name>.txt. It returns a problem for each invalid name.
+func parseColumns(names []string) ([]column, []string) {
+	if len(names) < 3 {
+		return nil, []string{"columns needs at least 3 names: a backlog, a ready and a done column"}
+	}
+
+	var problems []string
+	cols := make([]column, len(names))
+	seen := map[string]string{}
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		file := stageFile(name)
+		switch i {
+		case 0:
+			file = backlogFile
+		case 1:
+			file = readyFile
+		case len(names) - 1:
+			file = completedFile
+		}
+
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("columns: column %d has no name", i+1))
+			continue
+		}
+		if file == "" {
+			problems = append(problems, fmt.Sprintf("columns: %q needs a letter or digit in its name", name))
+			continue
+		}
+		if strings.HasPrefix(file, "todo_completed_backup") {
+			problems = append(problems, fmt.Sprintf("columns: %q would be mistaken for a completed backup file", name))
+			continue
+		}
+		if other, ok := seen[strings.ToLower(name)]; ok {
+			problems = append(problems, fmt.Sprintf("columns: %q is listed twice", other))
+			continue
+		}
+		if other, ok := seen[file]; ok {
+			problems = append(problems, fmt.Sprintf("columns: %q and %q would both be saved in %s", other, name, file))
+			continue
+		}
+		seen[strings.ToLower(name)] = name
+		seen[file] = name
+		cols[i] = column{name: name, file: file}
+	}
+	return cols, problems
+}
+
+// columnSlug returns the name used for a column in file names and CLI commands, e.g.
+// in_progress for "In Progress", or "" if the name has no letters or digits
+func columnSlug(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "_")
+}
+
+// stageFile returns the file a stage column is saved in, e.g. todo_in_progress.txt for
+// "In Progress", or "" if the name has no letters or digits
+func stageFile(name string) string {
+	if slug := columnSlug(name); slug != "" {
+		return "todo_" + slug + ".txt"
+	}
+	return ""
+}
+
+// stageCount returns how many stage columns sit between ready and the done column
+func stageCount() int {
+	return len(cfg.columns) - 3
+}
+
+// stageView returns the view of the i-th stage column
+func stageView(i int) view {
+	return viewCompleted + 1 + view(i)
+}
+
+// columnOrder returns the views in the order their columns are shown
+func columnOrder() []view {
+	order := []view{viewBacklog, viewReady}
+	for i := 0; i < stageCount(); i++ {
+		order = append(order, stageView(i))
+	}
+	return append(order, viewCompleted)
+}
+
+// columnOf returns the column shown in v
+func columnOf(v view) column {
+	switch v {
+	case viewBacklog:
+		return cfg.columns[0]
+	case viewReady:
+		return cfg.columns[1]
+	case viewCompleted:
+		return cfg.columns[len(cfg.columns)-1]
+	default:
+		return cfg.columns[2+int(v-stageView(0))]
+	}
+}
+
+// viewName returns the display name of a list
+func viewName(v view) string {
+	return columnOf(v).name
+}
+
+// adjacentView returns the view of the column delta places from v, or false if there
+// is none
+func adjacentView(v view, delta int) (view, bool) {
+	order := columnOrder()
+	for i, candidate := range order {
+		if candidate == v && i+delta >= 0 && i+delta < len(order) {
+			return order[i+delta], true
+		}
+	}
+	return v, false
+}
+
+// listFor returns the stored todos of the column shown in v, or nil for the done
+// column, whose todos are edited through displayedCompleted
+func (m *Model) listFor(v view) *[]Todo {
+	switch v {
+	case viewBacklog:
+		return &m.backlog
+	case viewReady:
+		return &m.ready
+	case viewCompleted:
+		return nil
+	default:
+		i := int(v - stageView(0))
+		for len(m.stages) <= i {
+			m.stages = append(m.stages, nil)
+		}
+		return &m.stages[i]
+	}
+}
+
+// switchView shows the column v with the cursor at the top, or on the board at the
+// same row as before so moving across columns feels like moving sideways
+func (m *Model) switchView(v view) {
+	m.currentView = v
+	m.updateDisplayedCompleted()
+	if m.showingBoard {
+		m.clampCursor()
+	} else {
+		m.cursor = 0
+	}
+	m.message = ""
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+}
+
+// moveSelected moves the todo under the cursor to the column shown in dest. Moving
+// into the done column completes the todo, bringing recurring ones back with their
+// next due date, and moving out of it clears CompletedAt. Todos moved to the backlog
+// go to its top.
+func (m *Model) moveSelected(dest view) tea.Cmd {
+	source := m.currentView
+	var todo Todo
+	if source == viewCompleted {
+		if m.cursor >= len(m.displayedCompleted) {
+			return nil
+		}
+		i := findTodoIndex(m.completed, m.displayedCompleted[m.cursor])
+		if i < 0 {
+			return nil
+		}
+		m.pushUndo()
+		todo = m.completed[i]
+		todo.CompletedAt = nil
+		m.completed = append(m.completed[:i], m.completed[i+1:]...)
+	} else {
+		idx := m.selectedIndex()
+		if idx < 0 {
+			return nil
+		}
+		list := m.listFor(source)
+		if dest == viewCompleted {
+			if err := checklistBlocksCompletion((*list)[idx]); err != nil {
+				m.message = "Can't complete yet: " + err.Error()
+				return nil
+			}
+		}
+		m.pushUndo()
+		todo = (*list)[idx]
+		*list = append((*list)[:idx], (*list)[idx+1:]...)
+	}
+
+	// The destination is saved before the source, so a failed save can't lose the todo
+	changed := []view{dest}
+	destName := strings.ToLower(viewName(dest))
+	now := time.Now()
+	if !timed(dest) {
+		stopTimer(&todo, now)
+	}
+	switch dest {
+	case viewCompleted:
+		todo.CompletedAt = &now
+		m.completed = append(m.completed, todo)
+		m.message = "Todo completed!"
+
+		// Recurring todos come back with their next due date
+		if next, ok := nextOccurrence(todo, now); ok {
+			if belongsInReady(next, now) {
+				m.ready = append(m.ready, next)
+				changed = append(changed, viewReady)
+			} else {
+				m.backlog = append([]Todo{next}, m.backlog...)
+				changed = append(changed, viewBacklog)
+			}
+			m.message = fmt.Sprintf("Todo completed! Next one %s", formatDueDate(*next.DueAt, now))
+		}
+	case viewBacklog:
+		m.backlog = append([]Todo{todo}, m.backlog...)
+		m.message = fmt.Sprintf("Todo moved to %s!", destName)
+	default:
+		list := m.listFor(dest)
+		*list = append(*list, todo)
+		m.message = fmt.Sprintf("Todo moved to %s!", destName)
+		if source == viewCompleted {
+			m.message = fmt.Sprintf("Todo moved back to %s!", destName)
+		}
+	}
+
+	m.updateDisplayedCompleted()
+	m.clampCursor()
+	if cmd := m.saveViews(append(changed, source)...); cmd != nil {
+		return cmd
+	}
+	return m.startBoardAnimation(todo.Text, source, dest)
+}
+
+// saveViews saves the files of the columns shown in views
+func (m *Model) saveViews(views ...view) tea.Cmd {
+	saved := map[view]bool{}
+	for _, v := range views {
+		if saved[v] {
+			continue
+		}
+		saved[v] = true
+		todos := m.completed
+		if list := m.listFor(v); list != nil {
+			todos = *list
+		}
+		if cmd := m.save(columnOf(v).file, todos); cmd != nil {
+			return cmd
+		}
+	}
+	return nil
+}
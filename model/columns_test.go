@@ -0,0 +1,235 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useColumns sets the workflow columns for the rest of the test
+func useColumns(t *testing.T, names ...string) {
+	t.Helper()
+	columns, problems := parseColumns(names)
+	if len(problems) > 0 {
+		t.Fatalf("parseColumns(%v) problems = %v", names, problems)
+	}
+	cfg.columns = columns
+	t.Cleanup(func() { cfg = defaultConfig() })
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, problems := parseColumns([]string{"To do", "Next", "In Progress", "Code review", "Done"})
+	if len(problems) > 0 {
+		t.Fatalf("parseColumns() problems = %v", problems)
+	}
+	expected := []column{
+		{"To do", backlogFile},
+		{"Next", readyFile},
+		{"In Progress", "todo_in_progress.txt"},
+		{"Code review", "todo_code_review.txt"},
+		{"Done", completedFile},
+	}
+	for i, col := range expected {
+		if columns[i] != col {
+			t.Errorf("columns[%d] = %+v, want %+v", i, columns[i], col)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr string
+	}{
+		{"too few", []string{"Todo", "Done"}, "at least 3"},
+		{"empty name", []string{"Backlog", "  ", "Done"}, "column 2 has no name"},
+		{"duplicate", []string{"Backlog", "Ready", "ready", "Done"}, `"Ready" is listed twice`},
+		{"same file", []string{"Backlog", "Ready", "In progress", "In-Progress!", "Done"}, "would both be saved in todo_in_progress.txt"},
+		{"no letters", []string{"Backlog", "Ready", "???", "Done"}, "needs a letter or digit"},
+		{"backup name", []string{"Backlog", "Ready", "Completed backup 2", "Done"}, "completed backup file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, problems := parseColumns(tt.names)
+			if !strings.Contains(strings.Join(problems, "\n"), tt.wantErr) {
+				t.Errorf("parseColumns(%v) problems = %v, want %q", tt.names, problems, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateWorkflowColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	useColumns(t, "Backlog", "Ready", "In Progress", "Review", "Done")
+
+	m := Model{
+		currentView: viewReady,
+		ready:       []Todo{{ID: "1", Text: "Write docs", CreatedAt: time.Now()}},
+	}
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "IN PROGRESS") || !strings.Contains(view, "REVIEW") || !strings.Contains(view, "DONE") {
+		t.Errorf("View should show a tab per column:\n%s", view)
+	}
+
+	// '>' moves the todo one column right
+	press(">")
+	if len(m.ready) != 0 || len(m.stages) == 0 || len(m.stages[0]) != 1 {
+		t.Fatalf("ready = %v, stages = %v, want the todo in progress", m.ready, m.stages)
+	}
+	if m.message != "Todo moved to in progress!" {
+		t.Errorf("message = %q", m.message)
+	}
+	if saved := loadTodos("todo_in_progress.txt"); len(saved) != 1 {
+		t.Errorf("saved in progress len = %d, want 1", len(saved))
+	}
+
+	// 'l' walks through the columns in order
+	press("l")
+	if m.currentView != stageView(0) {
+		t.Fatalf("currentView = %v, want In Progress", m.currentView)
+	}
+	press(">")
+	press("l")
+	if m.currentView != stageView(1) || len(m.getCurrentList()) != 1 {
+		t.Fatalf("currentView = %v with %d todos, want Review with the todo", m.currentView, len(m.getCurrentList()))
+	}
+
+	// Moving into the done column completes the todo
+	press(">")
+	if len(m.completed) != 1 || m.completed[0].CompletedAt == nil {
+		t.Fatalf("completed = %v, want the todo with CompletedAt set", m.completed)
+	}
+	press("l")
+	if m.currentView != viewCompleted {
+		t.Fatalf("currentView = %v, want Done", m.currentView)
+	}
+	press("l")
+	if m.currentView != viewCompleted {
+		t.Errorf("'l' should stop at the last column")
+	}
+
+	// Moving back out clears CompletedAt
+	press("<")
+	if len(m.completed) != 0 || len(m.stages[1]) != 1 || m.stages[1][0].CompletedAt != nil {
+		t.Fatalf("completed = %v, review = %v, want the todo back in review", m.completed, m.stages[1])
+	}
+	if m.message != "Todo moved back to review!" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	// Undo restores the stage columns too
+	press("U")
+	if len(m.completed) != 1 || len(m.stages[1]) != 0 {
+		t.Errorf("after undo completed = %v, review = %v, want the todo done", m.completed, m.stages[1])
+	}
+	if saved := loadTodos("todo_review.txt"); len(saved) != 0 {
+		t.Errorf("saved review len = %d, want 0 after undo", len(saved))
+	}
+}
+
+func TestUpdateStageColumnActions(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	useColumns(t, "Backlog", "Ready", "Doing", "Done")
+
+	m := Model{
+		currentView: stageView(0),
+		stages:      [][]Todo{{{ID: "1", Text: "First"}, {ID: "2", Text: "Second"}}},
+	}
+
+	// Adding works in a stage column and saves its file
+	m.adding = true
+	m.newTodo = "third"
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(m.stages[0]) != 3 || m.stages[0][2].Text != "Third" {
+		t.Fatalf("doing = %v, want the new todo added", m.stages[0])
+	}
+	if saved := loadTodos("todo_doing.txt"); len(saved) != 3 {
+		t.Errorf("saved doing len = %d, want 3", len(saved))
+	}
+
+	// 'b' sends a todo to the top of the backlog and 'x' completes one
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = updated.(Model)
+	if len(m.backlog) != 1 || m.backlog[0].ID != "1" {
+		t.Errorf("backlog = %v, want the first todo", m.backlog)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.completed) != 1 || m.completed[0].ID != "2" {
+		t.Errorf("completed = %v, want the second todo", m.completed)
+	}
+}
+
+func TestMoveSavesDestinationFirst(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	ready := []Todo{{ID: "1", Text: "Ship it"}}
+	saveTodos(readyFile, ready)
+	m := Model{currentView: viewReady, ready: ready}
+	m.trackFile(readyFile, ready)
+	// A directory in its place makes saving the completed file fail
+	os.Mkdir(completedFile, 0755)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	if cmd == nil || m.SaveError() == "" {
+		t.Fatal("a failed save should quit with the error")
+	}
+	if saved := loadTodos(readyFile); len(saved) != 1 {
+		t.Errorf("ready file = %+v, want the todo kept when it couldn't be saved elsewhere", saved)
+	}
+}
+
+func TestRunCommandWorkflowColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	useColumns(t, "Backlog", "Ready", "In Progress", "Done")
+
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Ship it"}})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"move", "1", "in-progress"}, &out); err != nil {
+		t.Fatalf("RunCommand(move) error = %v", err)
+	}
+	out.Reset()
+	if err := RunCommand([]string{"list", "in_progress"}, &out); err != nil {
+		t.Fatalf("RunCommand(list) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Ship it") {
+		t.Errorf("list output = %q, want the moved todo", out.String())
+	}
+
+	// The done column can be named by its own name as well as "completed"
+	if err := RunCommand([]string{"move", "1", "done", "--from", "in_progress"}, &out); err != nil {
+		t.Fatalf("RunCommand(move done) error = %v", err)
+	}
+	if completed := loadTodos(completedFile); len(completed) != 1 || completed[0].CompletedAt == nil {
+		t.Errorf("completed = %v, want the todo completed", completed)
+	}
+
+	err := RunCommand([]string{"list", "review"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "expected backlog, ready, in_progress or completed") {
+		t.Errorf("RunCommand(list review) error = %v, want the column names", err)
+	}
+}
@@ -0,0 +1,433 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// configEnv overrides the default config file location
+const configEnv = "TODO_CONFIG"
+
+// config holds the user-configurable behaviour, loaded once at startup
+type config struct {
+	completedLimit   int                 // How many completed todos the Completed page shows
+	weekStart        time.Weekday        // First day of the week in prettify view and export
+	widthMargin      int                 // Columns reserved beside todo text for the cursor, timestamps and indicators
+	capitalizeFirst  bool                // Capitalize the first letter of new and renamed todos
+	requireChecklist bool                // Refuse to complete todos with unfinished checklist items
+	author           *string             // Name recorded on new updates, nil to use $USER
+	backupKeepLast   int                 // Startup backups always kept, most recent first
+	backupKeepDaily  int                 // Days back for which the newest backup of each day is kept
+	backupKeepWeekly int                 // Weeks back for which the newest backup of each week is kept
+	backupCompress   bool                // Compress backups with gzip
+	keys             map[string]string   // Pressed key -> default key of the action it is bound to
+	searchKeys       map[string]string   // Same as keys, for the keys that only apply while searching
+	disabledKeys     map[string]bool     // Default keys whose action was rebound to other keys
+	keymap           map[string][]string // Action -> keys, for actions the user rebound
+	theme            string              // Theme name, "auto" to suit the terminal
+	themes           map[string]theme    // User-defined themes
+	columns          []column            // Workflow columns, in order (see parseColumns)
+}
+
+// cfg is the active configuration
+var cfg = defaultConfig()
+
+// defaultConfig returns the built-in behaviour used when there is no config file
+func defaultConfig() config {
+	return config{
+		completedLimit:   10,
+		weekStart:        time.Sunday,
+		widthMargin:      35,
+		capitalizeFirst:  true,
+		backupKeepLast:   10,
+		backupKeepDaily:  7,
+		backupKeepWeekly: 4,
+		theme:            "auto",
+		columns:          defaultColumns(),
+	}
+}
+
+// keyAction is a rebindable action and the keys bound to it by default. The first
+// default key is the one handled in Update.
+type keyAction struct {
+	name        string
+	defaultKeys []string
+}
+
+// keyActions lists every action that can be rebound in the config file's keymap
+var keyActions = []keyAction{
+	{"up", []string{"k"}},
+	{"down", []string{"j"}},
+	{"left", []string{"h"}},
+	{"right", []string{"l"}},
+	{"top", []string{"g"}},
+	{"bottom", []string{"G"}},
+	{"half_page_down", []string{"ctrl+d"}},
+	{"half_page_up", []string{"ctrl+u"}},
+	{"move_down", []string{"J"}},
+	{"move_up", []string{"K"}},
+	{"move_to_top", []string{"t"}},
+	{"add", []string{"a"}},
+	{"add_to_top", []string{"A"}},
+	{"delete", []string{"d"}},
+	{"rename", []string{"n"}},
+	{"add_update", []string{"u"}},
+	{"complete_note", []string{"c"}},
+	{"toggle_updates", []string{"i"}},
+	{"toggle_all_updates", []string{"I"}},
+	{"navigate_updates", []string{"enter"}},
+	{"complete", []string{"x"}},
+	{"move_to_ready", []string{"r"}},
+	{"move_to_backlog", []string{"b"}},
+	{"move_left", []string{"<"}},
+	{"move_right", []string{">"}},
+	{"checklist", []string{"C"}},
+	{"timer", []string{"m"}},
+	{"board", []string{"v"}},
+	{"backup", []string{"B"}},
+	{"newer_page", []string{"["}},
+	{"older_page", []string{"]"}},
+	{"jump_to_date", []string{"@"}},
+	{"prettify", []string{"p"}},
+	{"export", []string{"P"}},
+	{"stats", []string{"%"}},
+	{"archives", []string{"O"}},
+	{"merge_archive", []string{"M"}},
+	{"search", []string{"/"}},
+	{"undo", []string{"ctrl+z", "U"}},
+	{"redo", []string{"ctrl+r"}},
+	{"edit_tags", []string{"T"}},
+	{"filter_tags", []string{"f"}},
+	{"clear_filter", []string{"F"}},
+	{"due_date", []string{"D"}},
+	{"repeat", []string{"R"}},
+	{"sort", []string{"S"}},
+	{"raise_priority", []string{"+", "="}},
+	{"lower_priority", []string{"-"}},
+	{"help", []string{"?"}},
+	{"quit", []string{"q", "ctrl+c"}},
+}
+
+// searchActions can be rebound like keyActions, but only apply while a search is
+// active, so they may share keys with other actions
+var searchActions = []keyAction{
+	{"next_match", []string{"n"}},
+	{"previous_match", []string{"N"}},
+}
+
+// configFile is the JSON layout of the config file. Pointers distinguish settings
+// that were left out from ones set to their zero value.
+type configFile struct {
+	CompletedLimit   *int                         `json:"completed_limit"`
+	WeekStart        *string                      `json:"week_start"`
+	WidthMargin      *int                         `json:"width_margin"`
+	CapitalizeFirst  *bool                        `json:"capitalize_first"`
+	RequireChecklist *bool                        `json:"require_checklist"`
+	Author           *string                      `json:"author"`
+	BackupKeepLast   *int                         `json:"backup_keep_last"`
+	BackupKeepDaily  *int                         `json:"backup_keep_daily"`
+	BackupKeepWeekly *int                         `json:"backup_keep_weekly"`
+	BackupCompress   *bool                        `json:"backup_compress"`
+	Keymap           map[string]keyList           `json:"keymap"`
+	Theme            *string                      `json:"theme"`
+	Themes           map[string]map[string]string `json:"themes"`
+	Columns          []string                     `json:"columns"`
+}
+
+// keyList is the keys bound to an action, written as one key or a list of keys
+type keyList []string
+
+// UnmarshalJSON accepts either a single key string or an array of them
+func (k *keyList) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*k = keyList{key}
+		return nil
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return errors.New("keymap keys must be a string or a list of strings")
+	}
+	*k = keys
+	return nil
+}
+
+// ConfigPath picks the config file: --config, then $TODO_CONFIG, then config.json in
+// $XDG_CONFIG_HOME/todo (~/.config/todo). required reports whether the file was asked
+// for explicitly, so it is an error for it to be missing.
+func ConfigPath(opts Options) (path string, required bool) {
+	if opts.Config != "" {
+		return opts.Config, true
+	}
+	if path := os.Getenv(configEnv); path != "" {
+		return path, true
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "todo", "config.json"), false
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".config", "todo", "config.json"), false
+}
+
+// LoadConfig reads the config file at path and makes it the active configuration.
+// A missing file is only an error when required is set. All validation problems are
+// reported together.
+func LoadConfig(path string, required bool) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	loaded, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	cfg = loaded
+	return nil
+}
+
+// parseConfig parses and validates config file contents on top of the defaults
+func parseConfig(data []byte) (config, error) {
+	c := defaultConfig()
+
+	var file configFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line := bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
+			return c, fmt.Errorf("  line %d: %v", line, err)
+		}
+		return c, fmt.Errorf("  %v", err)
+	}
+
+	var problems []string
+	if file.CompletedLimit != nil {
+		if *file.CompletedLimit < 1 {
+			problems = append(problems, fmt.Sprintf("completed_limit must be at least 1, got %d", *file.CompletedLimit))
+		} else {
+			c.completedLimit = *file.CompletedLimit
+		}
+	}
+	if file.WeekStart != nil {
+		if weekday, ok := weekdayNames[strings.ToLower(*file.WeekStart)]; ok {
+			c.weekStart = weekday
+		} else {
+			problems = append(problems, fmt.Sprintf("week_start must be a day of the week, got %q", *file.WeekStart))
+		}
+	}
+	if file.WidthMargin != nil {
+		if *file.WidthMargin < 0 {
+			problems = append(problems, fmt.Sprintf("width_margin can't be negative, got %d", *file.WidthMargin))
+		} else {
+			c.widthMargin = *file.WidthMargin
+		}
+	}
+	if file.CapitalizeFirst != nil {
+		c.capitalizeFirst = *file.CapitalizeFirst
+	}
+	if file.RequireChecklist != nil {
+		c.requireChecklist = *file.RequireChecklist
+	}
+	if file.Author != nil {
+		author := strings.TrimSpace(*file.Author)
+		c.author = &author
+	}
+	if file.BackupKeepLast != nil {
+		if *file.BackupKeepLast < 1 {
+			problems = append(problems, fmt.Sprintf("backup_keep_last must be at least 1, got %d", *file.BackupKeepLast))
+		} else {
+			c.backupKeepLast = *file.BackupKeepLast
+		}
+	}
+	for _, setting := range []struct {
+		name  string
+		value *int
+		field *int
+	}{
+		{"backup_keep_daily", file.BackupKeepDaily, &c.backupKeepDaily},
+		{"backup_keep_weekly", file.BackupKeepWeekly, &c.backupKeepWeekly},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		if *setting.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s can't be negative, got %d", setting.name, *setting.value))
+		} else {
+			*setting.field = *setting.value
+		}
+	}
+	if file.BackupCompress != nil {
+		c.backupCompress = *file.BackupCompress
+	}
+	problems = append(problems, c.setKeymap(file.Keymap)...)
+
+	if file.Columns != nil {
+		columns, columnProblems := parseColumns(file.Columns)
+		if len(columnProblems) == 0 {
+			c.columns = columns
+		}
+		problems = append(problems, columnProblems...)
+	}
+
+	themes, themeProblems := parseThemes(file.Themes)
+	c.themes = themes
+	problems = append(problems, themeProblems...)
+	if file.Theme != nil {
+		if problem := validThemeName(*file.Theme, themes); problem != "" {
+			problems = append(problems, problem)
+		} else {
+			c.theme = *file.Theme
+		}
+	}
+
+	if len(problems) > 0 {
+		return c, errors.New("  " + strings.Join(problems, "\n  "))
+	}
+	return c, nil
+}
+
+// setKeymap binds each action in keymap to its keys in place of the defaults,
+// returning a problem for each unknown action, missing key or key bound twice
+func (c *config) setKeymap(keymap map[string]keyList) []string {
+	if len(keymap) == 0 {
+		return nil
+	}
+
+	var problems []string
+	actions := map[string]keyAction{}
+	for _, action := range keyActions {
+		actions[action.name] = action
+	}
+	searching := map[string]bool{}
+	for _, action := range searchActions {
+		actions[action.name] = action
+		searching[action.name] = true
+	}
+
+	// Report problems in a stable order
+	names := make([]string, 0, len(keymap))
+	for name := range keymap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.keys = map[string]string{}
+	c.searchKeys = map[string]string{}
+	c.disabledKeys = map[string]bool{}
+	c.keymap = map[string][]string{}
+	for _, name := range names {
+		action, ok := actions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("keymap: unknown action %q", name))
+			continue
+		}
+		if len(keymap[name]) == 0 {
+			problems = append(problems, fmt.Sprintf("keymap: %s needs at least one key", name))
+			continue
+		}
+		c.keymap[name] = keymap[name]
+		for _, key := range action.defaultKeys {
+			if !searching[name] {
+				c.disabledKeys[key] = true
+			}
+		}
+	}
+
+	problems = append(problems, c.bindKeys(keyActions, c.keys)...)
+	problems = append(problems, c.bindKeys(searchActions, c.searchKeys)...)
+	return problems
+}
+
+// bindKeys fills keys with the rebound keys of actions, returning a problem for each
+// key that is empty, reserved or bound to two of the actions
+func (c *config) bindKeys(actions []keyAction, keys map[string]string) []string {
+	var problems []string
+	bound := map[string]string{}
+	for _, action := range actions {
+		actionKeys := action.defaultKeys
+		custom, rebound := c.keymap[action.name]
+		if rebound {
+			actionKeys = custom
+		}
+		for _, key := range actionKeys {
+			switch other, ok := bound[key]; {
+			case strings.TrimSpace(key) == "":
+				problems = append(problems, fmt.Sprintf("keymap: %s has an empty key", action.name))
+			case key == "esc":
+				problems = append(problems, fmt.Sprintf("keymap: %s can't use esc, which always cancels", action.name))
+			case ok:
+				problems = append(problems, fmt.Sprintf("keymap: %q is bound to both %s and %s", key, other, action.name))
+			default:
+				bound[key] = action.name
+				if rebound {
+					keys[key] = action.defaultKeys[0]
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// translateKey maps a pressed key to the default key of the action it is bound to,
+// so Update can keep matching on the default keys. Default keys of rebound actions
+// are ignored unless they are now bound to something else.
+func translateKey(key string) string {
+	if mapped, ok := cfg.keys[key]; ok {
+		return mapped
+	}
+	if cfg.disabledKeys[key] {
+		return ""
+	}
+	return key
+}
+
+// translateSearchKey is translateKey for the keys that jump between search matches
+func translateSearchKey(key string) string {
+	if mapped, ok := cfg.searchKeys[key]; ok {
+		return mapped
+	}
+	if _, rebound := cfg.keymap["next_match"]; rebound && key == "n" {
+		return ""
+	}
+	if _, rebound := cfg.keymap["previous_match"]; rebound && key == "N" {
+		return ""
+	}
+	return key
+}
+
+// formatTodoText applies the configured formatting to the text of a new or renamed todo
+func formatTodoText(text string) string {
+	if cfg.capitalizeFirst {
+		return capitalizeFirst(text)
+	}
+	return text
+}
+
+// customKeysHelp describes rebound actions for the help footer, or "" if none are
+func customKeysHelp() string {
+	var parts []string
+	for _, action := range append(append([]keyAction{}, keyActions...), searchActions...) {
+		if keys, ok := cfg.keymap[action.name]; ok {
+			parts = append(parts, fmt.Sprintf("%s: %s", strings.ReplaceAll(action.name, "_", " "), strings.Join(keys, "/")))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
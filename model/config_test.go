@@ -0,0 +1,200 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr []string
+	}{
+		{"empty object", `{}`, nil},
+		{"all settings", `{"completed_limit": 25, "week_start": "Monday", "width_margin": 20, "capitalize_first": false, "require_checklist": true}`, nil},
+		{"keymap", `{"keymap": {"up": ["up", "w"], "down": "s"}}`, nil},
+		{"syntax error", "{\n  \"completed_limit\": 5,\n}", []string{"line 3"}},
+		{"unknown setting", `{"completed_limt": 5}`, []string{`unknown field "completed_limt"`}},
+		{"wrong type", `{"capitalize_first": "yes"}`, []string{"capitalize_first"}},
+		{"bad key list", `{"keymap": {"up": 5}}`, []string{"string or a list of strings"}},
+		{
+			"every problem reported",
+			`{"completed_limit": 0, "week_start": "someday", "width_margin": -1}`,
+			[]string{"completed_limit must be at least 1", `week_start must be a day of the week, got "someday"`, "width_margin can't be negative"},
+		},
+		{"unknown action", `{"keymap": {"jump": "x"}}`, []string{`unknown action "jump"`}},
+		{"no keys", `{"keymap": {"up": []}}`, []string{"up needs at least one key"}},
+		{"clashes with default", `{"keymap": {"up": "j"}}`, []string{`"j" is bound to both up and down`}},
+		{"esc reserved", `{"keymap": {"quit": "esc"}}`, []string{"can't use esc"}},
+		{"search keys may overlap", `{"keymap": {"next_match": "j"}}`, nil},
+		{"columns", `{"columns": ["Backlog", "Ready", "Doing", "Done"]}`, nil},
+		{"too few columns", `{"columns": ["Todo", "Done"]}`, []string{"at least 3"}},
+		{"backups", `{"backup_keep_last": 3, "backup_keep_daily": 0, "backup_keep_weekly": 8, "backup_compress": true}`, nil},
+		{
+			"bad backup retention",
+			`{"backup_keep_last": 0, "backup_keep_weekly": -1}`,
+			[]string{"backup_keep_last must be at least 1", "backup_keep_weekly can't be negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.data))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("parseConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("parseConfig() should return an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("parseConfig() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	defer func() { cfg = defaultConfig() }()
+	tmpDir := t.TempDir()
+
+	// A missing default config is fine, but a missing explicit one is not
+	missing := filepath.Join(tmpDir, "missing.json")
+	if err := LoadConfig(missing, false); err != nil {
+		t.Errorf("LoadConfig(missing, false) error = %v", err)
+	}
+	if err := LoadConfig(missing, true); err == nil {
+		t.Error("LoadConfig(missing, true) should return an error")
+	}
+
+	path := filepath.Join(tmpDir, "config.json")
+	os.WriteFile(path, []byte(`{"completed_limit": 3, "week_start": "mon"}`), 0644)
+	if err := LoadConfig(path, true); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.completedLimit != 3 || cfg.weekStart != time.Monday || cfg.widthMargin != 35 {
+		t.Errorf("cfg = %+v, want limit 3, Monday week start and default margin", cfg)
+	}
+
+	// An invalid file names the file and leaves the loaded config alone
+	os.WriteFile(path, []byte(`{"completed_limit": -2}`), 0644)
+	err := LoadConfig(path, true)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadConfig() error = %v, want it to name the file", err)
+	}
+	if cfg.completedLimit != 3 {
+		t.Errorf("completedLimit = %d, want 3 kept", cfg.completedLimit)
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	t.Setenv(configEnv, "")
+
+	if path, required := ConfigPath(Options{}); path != filepath.Join("/xdg", "todo", "config.json") || required {
+		t.Errorf("ConfigPath() = %q, %v, want optional XDG config", path, required)
+	}
+	t.Setenv(configEnv, "/env.json")
+	if path, required := ConfigPath(Options{}); path != "/env.json" || !required {
+		t.Errorf("ConfigPath() = %q, %v, want required $TODO_CONFIG", path, required)
+	}
+	if path, required := ConfigPath(Options{Config: "flag.json"}); path != "flag.json" || !required {
+		t.Errorf("ConfigPath() = %q, %v, want required --config", path, required)
+	}
+}
+
+func TestUpdateWithKeymap(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	loaded, err := parseConfig([]byte(`{"keymap": {"up": "up", "down": ["down", "s"], "complete": "enter", "navigate_updates": "o", "next_match": "s"}}`))
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	cfg = loaded
+	defer func() { cfg = defaultConfig() }()
+
+	m := Model{
+		currentView: viewReady,
+		ready:       []Todo{{ID: "1", Text: "First"}, {ID: "2", Text: "Second"}, {ID: "3", Text: "Third"}},
+	}
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.cursor != 1 {
+		t.Fatalf("cursor = %d after 's', want 1", m.cursor)
+	}
+	// The old default key no longer moves
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d after 'k', want it unchanged", m.cursor)
+	}
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d after up arrow, want 0", m.cursor)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.completed) != 1 || m.completed[0].ID != "1" {
+		t.Fatalf("completed = %v, want enter to complete the todo", m.completed)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if len(m.completed) != 1 {
+		t.Errorf("completed len = %d, want 'x' to do nothing", len(m.completed))
+	}
+
+	m.showingCommands = true
+	if !strings.Contains(m.View(), "Custom keys: up: up  down: down/s") {
+		t.Error("help should list the custom keys")
+	}
+}
+
+func TestConfiguredBehaviour(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	defer func() { cfg = defaultConfig() }()
+
+	cfg.completedLimit = 2
+	cfg.weekStart = time.Monday
+	cfg.capitalizeFirst = false
+
+	// Saturday, October 17 2026
+	saturday := time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local)
+	if start := getWeekStart(saturday); !start.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("getWeekStart() = %v, want Monday the 12th", start)
+	}
+	// Sunday is the end of a Monday-based week
+	if start := getWeekStart(saturday.AddDate(0, 0, 1)); !start.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("getWeekStart(Sunday) = %v, want Monday the 12th", start)
+	}
+
+	m := Model{completed: make([]Todo, 5)}
+	m.updateDisplayedCompleted()
+	if len(m.displayedCompleted) != 2 {
+		t.Errorf("displayedCompleted len = %d, want 2", len(m.displayedCompleted))
+	}
+
+	m = Model{currentView: viewReady, adding: true, newTodo: "lowercase task"}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if text := updated.(Model).ready[0].Text; text != "lowercase task" {
+		t.Errorf("Text = %q, want it left as typed", text)
+	}
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// dataDirEnv overrides the default data directory
+const dataDirEnv = "TODO_DIR"
+
+// Options are the global flags accepted before or after any subcommand
+type Options struct {
+	Dir    string // --dir: directory holding the todo files
+	List   string // --list: named list stored in its own subdirectory of Dir
+	Config string // --config: config file to load instead of the default one
+}
+
+// activeList is the named list in use, shown in the UI header
+var activeList string
+
+// ParseOptions removes the global --dir, --list and --config flags from args, returning them
+// along with the remaining arguments
+func ParseOptions(args []string) (Options, []string, error) {
+	var opts Options
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !strings.HasPrefix(arg, "--") || (name != "dir" && name != "list" && name != "config") {
+			rest = append(rest, arg)
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("flag --%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if strings.TrimSpace(value) == "" {
+			return opts, nil, fmt.Errorf("flag --%s requires a value", name)
+		}
+		switch name {
+		case "dir":
+			opts.Dir = value
+		case "list":
+			opts.List = value
+		default:
+			opts.Config = value
+		}
+	}
+
+	if opts.List != "" && (strings.ContainsAny(opts.List, `/\`) || opts.List == "." || opts.List == "..") {
+		return opts, nil, fmt.Errorf("invalid list name %q", opts.List)
+	}
+	return opts, rest, nil
+}
+
+// ResolveDataDir picks the directory holding the todo files: --dir, then $TODO_DIR,
+// then the working directory if it already has todo files (how older versions
+// worked), then $XDG_DATA_HOME/todo (~/.local/share/todo). A named list lives in a
+// subdirectory of that directory.
+func ResolveDataDir(opts Options) (string, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = os.Getenv(dataDirEnv)
+	}
+	if dir == "" && hasTodoFiles(".") {
+		dir = "."
+	}
+	if dir == "" {
+		defaultDir, err := defaultDataDir()
+		if err != nil {
+			return "", err
+		}
+		dir = defaultDir
+	}
+
+	if opts.List != "" {
+		dir = filepath.Join(dir, opts.List)
+	}
+	return filepath.Abs(dir)
+}
+
+// defaultDataDir returns the XDG data directory for todo files
+func defaultDataDir() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "todo"), nil
+	}
+	if runtime.GOOS == "windows" {
+		if appData, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(appData, "todo"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("can't find a home directory for the todo files; use --dir or TODO_DIR")
+	}
+	return filepath.Join(home, ".local", "share", "todo"), nil
+}
+
+// hasTodoFiles reports whether dir contains any of the todo list files
+func hasTodoFiles(dir string) bool {
+	for _, filename := range []string{backlogFile, readyFile, completedFile} {
+		if _, err := os.Stat(filepath.Join(dir, filename)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// UseDataDir creates dir if needed and makes it the working directory, so the todo
+// files, backups and exports are all read and written there. list is the named list
+// being used, if any.
+func UseDataDir(dir, list string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("opening data directory: %w", err)
+	}
+	activeList = list
+	return nil
+}
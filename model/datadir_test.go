@@ -0,0 +1,117 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected Options
+		rest     []string
+		wantErr  bool
+	}{
+		{"no options", []string{"list", "ready"}, Options{}, []string{"list", "ready"}, false},
+		{"dir before command", []string{"--dir", "/tmp/todos", "add", "task"}, Options{Dir: "/tmp/todos"}, []string{"add", "task"}, false},
+		{"list after command", []string{"add", "task", "--list=work"}, Options{List: "work"}, []string{"add", "task"}, false},
+		{"both", []string{"--list", "home", "--dir=/data"}, Options{Dir: "/data", List: "home"}, nil, false},
+		{"config", []string{"--config", "keys.json", "list"}, Options{Config: "keys.json"}, []string{"list"}, false},
+		{"other flags kept", []string{"add", "task", "--top"}, Options{}, []string{"add", "task", "--top"}, false},
+		{"after double dash", []string{"add", "--", "--list", "x"}, Options{}, []string{"add", "--", "--list", "x"}, false},
+		{"missing value", []string{"--dir"}, Options{}, nil, true},
+		{"empty value", []string{"--list="}, Options{}, nil, true},
+		{"list with separator", []string{"--list", "../work"}, Options{}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, rest, err := ParseOptions(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOptions(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if opts != tt.expected {
+				t.Errorf("ParseOptions(%v) options = %+v, want %+v", tt.args, opts, tt.expected)
+			}
+			if !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("ParseOptions(%v) rest = %v, want %v", tt.args, rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestResolveDataDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	xdg := filepath.Join(tmpDir, "xdg")
+	t.Setenv("XDG_DATA_HOME", xdg)
+	t.Setenv(dataDirEnv, "")
+
+	resolve := func(opts Options) string {
+		t.Helper()
+		dir, err := ResolveDataDir(opts)
+		if err != nil {
+			t.Fatalf("ResolveDataDir(%+v) error = %v", opts, err)
+		}
+		return dir
+	}
+
+	// Nothing set and no todo files here: XDG default
+	if dir := resolve(Options{}); dir != filepath.Join(xdg, "todo") {
+		t.Errorf("default dir = %q, want %q", dir, filepath.Join(xdg, "todo"))
+	}
+	if dir := resolve(Options{List: "work"}); dir != filepath.Join(xdg, "todo", "work") {
+		t.Errorf("named list dir = %q, want XDG subdirectory", dir)
+	}
+
+	// Existing todo files in the working directory keep being used
+	saveTodos(readyFile, []Todo{{Text: "existing"}})
+	wd, _ := os.Getwd()
+	if dir := resolve(Options{}); dir != wd {
+		t.Errorf("dir with local files = %q, want working directory %q", dir, wd)
+	}
+
+	// TODO_DIR beats the working directory, and --dir beats TODO_DIR
+	envDir := filepath.Join(tmpDir, "env")
+	t.Setenv(dataDirEnv, envDir)
+	if dir := resolve(Options{}); dir != envDir {
+		t.Errorf("dir from env = %q, want %q", dir, envDir)
+	}
+	flagDir := filepath.Join(tmpDir, "flag")
+	if dir := resolve(Options{Dir: flagDir, List: "home"}); dir != filepath.Join(flagDir, "home") {
+		t.Errorf("dir from flag = %q, want %q", dir, filepath.Join(flagDir, "home"))
+	}
+}
+
+func TestUseDataDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	defer func() { activeList = "" }()
+
+	dir := filepath.Join(tmpDir, "todos", "work")
+	if err := UseDataDir(dir, "work"); err != nil {
+		t.Fatalf("UseDataDir() error = %v", err)
+	}
+
+	// Files are now written to the data directory
+	saveTodos(readyFile, []Todo{{Text: "task"}})
+	if _, err := os.Stat(filepath.Join(dir, readyFile)); err != nil {
+		t.Errorf("ready file not created in data directory: %v", err)
+	}
+
+	m := Model{currentView: viewReady}
+	if !strings.Contains(m.View(), "list: work") {
+		t.Error("View should name the list in use")
+	}
+}
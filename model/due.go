@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dueSoonDays is how many days ahead a due date counts as "due soon"
+const dueSoonDays = 3
+
+// sortMode controls the order backlog and ready are displayed in. The saved order is
+// always the manual one.
+type sortMode int
+
+const (
+	sortManual sortMode = iota
+	sortDue
+	sortPriority
+)
+
+// sortedReorderMessage explains why J/K/t do nothing while backlog and ready are sorted
+const sortedReorderMessage = "Reordering is disabled while sorted (S to return to manual order)"
+
+// weekdayNames maps full and abbreviated weekday names to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseDueDate parses a due date relative to now. It accepts "today", "tomorrow",
+// weekday names ("fri", "monday": the next such day, today included), ISO dates
+// ("2026-11-03") and offsets ("+3d", "+2w"). The result is midnight local time.
+func parseDueDate(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today := truncateToDay(now)
+
+	switch input {
+	case "today", "tod":
+		return today, nil
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if weekday, ok := weekdayNames[input]; ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if strings.HasPrefix(input, "+") && len(input) > 2 {
+		n, err := strconv.Atoi(input[1 : len(input)-1])
+		if err == nil && n >= 0 {
+			switch input[len(input)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("can't understand due date %q (try tomorrow, fri, 2026-11-03 or +3d)", input)
+}
+
+// daysUntilDue returns the number of calendar days from now until the todo is due,
+// negative when overdue
+func daysUntilDue(due, now time.Time) int {
+	dueDay := truncateToDay(due)
+	today := truncateToDay(now)
+	// Round to absorb daylight saving shifts between the two midnights
+	return int(math.Round(dueDay.Sub(today).Hours() / 24))
+}
+
+// isDueSoon reports whether an unfinished todo is overdue or due within dueSoonDays
+func isDueSoon(todo Todo, now time.Time) bool {
+	return todo.DueAt != nil && todo.CompletedAt == nil && daysUntilDue(*todo.DueAt, now) <= dueSoonDays
+}
+
+// formatDueDate renders a due date relative to now, e.g. "due today", "due Fri" or "overdue 2d"
+func formatDueDate(due, now time.Time) string {
+	days := daysUntilDue(due, now)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("overdue %dd", -days)
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	case days < 7:
+		return "due " + due.Format("Mon")
+	case due.Year() == now.Year():
+		return "due " + due.Format("Jan 2")
+	default:
+		return "due " + due.Format("Jan 2, 2006")
+	}
+}
+
+// renderDueDate renders the todo's due date in the style matching how close it is
+func renderDueDate(todo Todo, now time.Time) string {
+	if todo.DueAt == nil {
+		return ""
+	}
+	text := "[" + formatDueDate(*todo.DueAt, now) + "]"
+	if todo.CompletedAt != nil {
+		return dueStyle.Render(text)
+	}
+	switch days := daysUntilDue(*todo.DueAt, now); {
+	case days < 0:
+		return overdueStyle.Render(text)
+	case days == 0:
+		return dueTodayStyle.Render(text)
+	default:
+		return dueStyle.Render(text)
+	}
+}
+
+// countDueSoon returns how many todos not yet done are due soon, and how many of
+// those are overdue
+func (m *Model) countDueSoon() (dueSoon, overdue int) {
+	now := time.Now()
+	for _, list := range append([][]Todo{m.backlog, m.ready}, m.stages...) {
+		for _, todo := range list {
+			if !isDueSoon(todo, now) {
+				continue
+			}
+			dueSoon++
+			if daysUntilDue(*todo.DueAt, now) < 0 {
+				overdue++
+			}
+		}
+	}
+	return dueSoon, overdue
+}
+
+// dueBefore orders todos by due date, with todos without one last
+func dueBefore(a, b Todo) bool {
+	switch {
+	case a.DueAt == nil:
+		return false
+	case b.DueAt == nil:
+		return true
+	default:
+		return a.DueAt.Before(*b.DueAt)
+	}
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseDueDate(t *testing.T) {
+	// Saturday, October 17 2026
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		update   string
+	}{
+		{"today", time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local), "Today at midnight"},
+		{"Tomorrow", time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local), "Case-insensitive keywords"},
+		{"fri", time.Date(2026, 10, 23, 0, 0, 0, 0, time.Local), "Next Friday"},
+		{"saturday", time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local), "Today's weekday means today"},
+		{"mon", time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local), "Weekday wraps into next week"},
+		{"2026-11-03", time.Date(2026, 11, 3, 0, 0, 0, 0, time.Local), "ISO date"},
+		{"+3d", time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local), "Day offset"},
+		{"+2w", time.Date(2026, 10, 31, 0, 0, 0, 0, time.Local), "Week offset"},
+		{" +0d ", time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local), "Whitespace is ignored"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseDueDate(tt.input, now)
+			if err != nil {
+				t.Fatalf("parseDueDate(%q) error = %v", tt.input, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("parseDueDate(%q) = %v, want %v (%s)", tt.input, got, tt.expected, tt.update)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "someday", "+3x", "+d", "2026-13-01"} {
+		if _, err := parseDueDate(invalid, now); err == nil {
+			t.Errorf("parseDueDate(%q) should return an error", invalid)
+		}
+	}
+}
+
+func TestFormatDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		due      time.Time
+		expected string
+	}{
+		{day(2026, 10, 15), "overdue 2d"},
+		{day(2026, 10, 17), "due today"},
+		{day(2026, 10, 18), "due tomorrow"},
+		{day(2026, 10, 21), "due Wed"},
+		{day(2026, 11, 3), "due Nov 3"},
+		{day(2027, 1, 5), "due Jan 5, 2027"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDueDate(tt.due, now); got != tt.expected {
+			t.Errorf("formatDueDate(%v) = %q, want %q", tt.due, got, tt.expected)
+		}
+	}
+}
+
+func TestCountDueSoon(t *testing.T) {
+	today := truncateToDay(time.Now())
+	at := func(days int) *time.Time {
+		due := today.AddDate(0, 0, days)
+		return &due
+	}
+
+	m := Model{
+		backlog: []Todo{
+			{Text: "overdue", DueAt: at(-1)},
+			{Text: "far off", DueAt: at(dueSoonDays + 1)},
+		},
+		ready: []Todo{
+			{Text: "today", DueAt: at(0)},
+			{Text: "soon", DueAt: at(dueSoonDays)},
+			{Text: "no due date"},
+		},
+	}
+
+	dueSoon, overdue := m.countDueSoon()
+	if dueSoon != 3 || overdue != 1 {
+		t.Errorf("countDueSoon() = %d, %d, want 3, 1", dueSoon, overdue)
+	}
+}
+
+func TestUpdateSetDueDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		ready:       []Todo{{ID: "1", Text: "Ship release", CreatedAt: time.Now()}},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	m = updated.(Model)
+	if !m.editingDue {
+		t.Fatal("'D' should open the due date prompt")
+	}
+
+	// Invalid input keeps the prompt open
+	m.newDue = "someday"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.editingDue || !strings.Contains(m.message, "can't understand") {
+		t.Fatalf("editingDue/message = %v/%q, want prompt kept open with an error", m.editingDue, m.message)
+	}
+
+	m.newDue = "today"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.editingDue || m.ready[0].DueAt == nil {
+		t.Fatal("Enter should save the due date")
+	}
+	if saved := loadTodos(readyFile); saved[0].DueAt == nil {
+		t.Error("due date should be saved to file")
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "due today") || !strings.Contains(view, "Due soon:") {
+		t.Error("View should show the due date and the due soon count")
+	}
+
+	// Reopening prefills the date; clearing it removes the due date
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	m = updated.(Model)
+	if m.newDue != truncateToDay(time.Now()).Format("2006-01-02") {
+		t.Errorf("newDue = %q, want today's date", m.newDue)
+	}
+	m.newDue = ""
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.ready[0].DueAt != nil || m.message != "Due date removed" {
+		t.Errorf("DueAt/message = %v/%q, want due date removed", m.ready[0].DueAt, m.message)
+	}
+}
+
+func TestUpdateSortByDueDate(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	today := truncateToDay(time.Now())
+	later := today.AddDate(0, 0, 5)
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "no date", CreatedAt: today},
+			{ID: "2", Text: "later", DueAt: &later, CreatedAt: today},
+			{ID: "3", Text: "today", DueAt: &today, CreatedAt: today},
+		},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	m = updated.(Model)
+	list := m.getCurrentList()
+	if list[0].ID != "3" || list[1].ID != "2" || list[2].ID != "1" {
+		t.Errorf("sorted ready = %v, want 3, 2, 1", list)
+	}
+
+	// Reordering is blocked and the saved order is untouched
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	if m.message != sortedReorderMessage || m.ready[0].ID != "1" {
+		t.Errorf("J while sorted: message = %q, ready[0] = %q", m.message, m.ready[0].ID)
+	}
+
+	// Actions apply to the todo shown under the cursor
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.completed) != 1 || m.completed[0].ID != "3" {
+		t.Errorf("completed = %v, want todo 3", m.completed)
+	}
+
+	// S cycles through priority order back to manual order
+	for i := 0; i < 2; i++ {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+		m = updated.(Model)
+	}
+	if m.sortMode != sortManual {
+		t.Fatalf("sortMode = %v, want manual", m.sortMode)
+	}
+	if list := m.getCurrentList(); list[0].ID != "1" {
+		t.Errorf("manual order ready[0] = %q, want 1", list[0].ID)
+	}
+}
+
+func TestRunCommandAddDue(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"add", "pay invoice", "--due", "tomorrow"}, &out); err != nil {
+		t.Fatalf("RunCommand(add --due) error = %v", err)
+	}
+	ready := loadTodos(readyFile)
+	if len(ready) != 1 || ready[0].DueAt == nil {
+		t.Fatalf("ready = %v, want one todo with a due date", ready)
+	}
+
+	out.Reset()
+	if err := RunCommand([]string{"list"}, &out); err != nil {
+		t.Fatalf("RunCommand(list) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "due tomorrow") {
+		t.Errorf("list output = %q, want due date", out.String())
+	}
+
+	if err := RunCommand([]string{"add", "x", "--due", "someday"}, &out); err == nil {
+		t.Error("RunCommand(add --due someday) should return an error")
+	}
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"sort"
@@ -12,13 +14,66 @@ import (
 
 // getCurrentList returns the list of todos for the current view
 func (m *Model) getCurrentList() []Todo {
-	switch m.currentView {
-	case viewBacklog:
-		return m.backlog
-	case viewReady:
-		return m.ready
-	default:
-		return m.displayedCompleted
+	if list := m.listFor(m.currentView); list != nil {
+		return m.visibleTodos(*list)
+	}
+	return m.displayedCompleted
+}
+
+// visibleTodos returns the todos of list shown in the current view, in display order
+func (m *Model) visibleTodos(list []Todo) []Todo {
+	if len(m.tagFilter) == 0 && m.sortMode == sortManual {
+		return list
+	}
+	visible := []Todo{}
+	for _, i := range m.visibleIndices() {
+		visible = append(visible, list[i])
+	}
+	return visible
+}
+
+// visibleIndices returns the indices into the current column's stored list of the todos
+// shown in the current view, in display order (filtered by tag and sorted by sortMode,
+// with manual order breaking ties). It returns nil for the Completed view.
+func (m *Model) visibleIndices() []int {
+	stored := m.listFor(m.currentView)
+	if stored == nil {
+		return nil
+	}
+	list := *stored
+
+	indices := make([]int, 0, len(list))
+	for i, todo := range list {
+		if m.matchesTagFilter(todo) {
+			indices = append(indices, i)
+		}
+	}
+	switch m.sortMode {
+	case sortDue:
+		sort.SliceStable(indices, func(a, b int) bool { return dueBefore(list[indices[a]], list[indices[b]]) })
+	case sortPriority:
+		sort.SliceStable(indices, func(a, b int) bool { return priorityBefore(list[indices[a]], list[indices[b]]) })
+	}
+	return indices
+}
+
+// selectedIndex returns the index into the current column's stored list of the todo
+// under the cursor, or -1 if there is none
+func (m *Model) selectedIndex() int {
+	indices := m.visibleIndices()
+	if m.cursor < 0 || m.cursor >= len(indices) {
+		return -1
+	}
+	return indices[m.cursor]
+}
+
+// clampCursor keeps the cursor on the last todo after one is removed from the end
+func (m *Model) clampCursor() {
+	if n := len(m.getCurrentList()); m.cursor >= n && m.cursor > 0 {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
 	}
 }
 
@@ -27,22 +82,76 @@ func (m *Model) updateCompletedTodo(updateFn func(*Todo)) {
 	if m.cursor >= len(m.displayedCompleted) {
 		return
 	}
-	todoToUpdate := m.displayedCompleted[m.cursor]
-	for i := range m.completed {
-		if m.completed[i].Text == todoToUpdate.Text && m.completed[i].CreatedAt.Equal(todoToUpdate.CreatedAt) {
-			updateFn(&m.completed[i])
-			break
-		}
+	if i := findTodoIndex(m.completed, m.displayedCompleted[m.cursor]); i >= 0 {
+		updateFn(&m.completed[i])
 	}
 	m.updateDisplayedCompleted()
 }
 
-// save wraps saveTodos and returns tea.Quit on failure
+// newTodoID returns a random identifier for a new todo
+func newTodoID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand only fails if the OS entropy source is unavailable
+		panic(fmt.Sprintf("generating todo id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// sameTodo reports whether a and b refer to the same todo. Todos are matched by ID,
+// falling back to text and creation time for todos that have never been assigned one.
+func sameTodo(a, b Todo) bool {
+	if a.ID != "" || b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Text == b.Text && a.CreatedAt.Equal(b.CreatedAt)
+}
+
+// findTodoIndex returns the index of target in todos, or -1 if it is not present
+func findTodoIndex(todos []Todo, target Todo) int {
+	for i, todo := range todos {
+		if sameTodo(todo, target) {
+			return i
+		}
+	}
+	return -1
+}
+
+// halfPage returns how many todos ctrl+d/ctrl+u move by: half the terminal height,
+// or a fixed default before the terminal size is known
+func (m *Model) halfPage() int {
+	if m.height <= 0 {
+		return 10
+	}
+	if m.height < 4 {
+		return 1
+	}
+	return m.height / 2
+}
+
+// save wraps saveTodos and returns tea.Quit on failure. Nothing is saved while
+// read-only. If another program changed the file since this session last loaded or
+// saved it, its changes are merged in rather than overwritten, and if it can't be
+// read it isn't overwritten at all.
 func (m *Model) save(filename string, todos []Todo) tea.Cmd {
+	if m.readOnly {
+		m.message = m.readOnlyMessage()
+		return nil
+	}
+	if m.changedOnDisk(filename) {
+		var conflicts []string
+		var err error
+		if todos, conflicts, err = m.mergeFromDisk(filename, todos); err != nil {
+			m.saveError = fmt.Sprintf("Failed to save %s: %v", filename, err)
+			return tea.Quit
+		}
+		m.warnChangedOnDisk(filename, conflicts)
+	}
 	if err := saveTodos(filename, todos); err != nil {
 		m.saveError = fmt.Sprintf("Failed to save %s: %v", filename, err)
 		return tea.Quit
 	}
+	m.trackFile(filename, todos)
 	return nil
 }
 
@@ -56,16 +165,26 @@ func swapTodos(list []Todo, idx1, idx2 int) {
 	list[idx1], list[idx2] = list[idx2], list[idx1]
 }
 
-// updateDisplayedCompleted sorts and limits the completed list for display
+// updateDisplayedCompleted sorts the completed history and shows the current page of it
 func (m *Model) updateDisplayedCompleted() {
-	if len(m.completed) == 0 {
+	// Count the backups here, as View works on a copy that can't keep the count
+	m.archivedCount()
+	history := m.completedHistory()
+	m.completedTotal = len(history)
+	m.completedPage = max(0, min(m.completedPage, m.completedPages()-1))
+	if len(history) == 0 {
 		m.displayedCompleted = []Todo{}
 		return
 	}
 
-	// Sort by completion time (most recent first)
-	sorted := make([]Todo, len(m.completed))
-	copy(sorted, m.completed)
+	start := m.completedPage * cfg.completedLimit
+	m.displayedCompleted = history[start:min(start+cfg.completedLimit, len(history))]
+}
+
+// sortByCompletedDesc returns a copy of todos sorted by completion time (most recent first)
+func sortByCompletedDesc(todos []Todo) []Todo {
+	sorted := make([]Todo, len(todos))
+	copy(sorted, todos)
 	sort.Slice(sorted, func(i, j int) bool {
 		if sorted[i].CompletedAt == nil {
 			return false
@@ -75,13 +194,7 @@ func (m *Model) updateDisplayedCompleted() {
 		}
 		return sorted[i].CompletedAt.After(*sorted[j].CompletedAt)
 	})
-
-	// Take only the first 10
-	if len(sorted) > 10 {
-		m.displayedCompleted = sorted[:10]
-	} else {
-		m.displayedCompleted = sorted
-	}
+	return sorted
 }
 
 // countCompletedToday returns the number of todos completed today
@@ -193,11 +306,11 @@ func groupTodosByWeek(todos []Todo) []WeekGroup {
 	return weeks
 }
 
-// getWeekStart returns the start of the week (Sunday) for the given date
+// getWeekStart returns the start of the week (Sunday unless configured) for the given date
 func getWeekStart(t time.Time) time.Time {
-	// Get the day of week (0 = Sunday, 1 = Monday, etc.)
-	dayOfWeek := int(t.Weekday())
-	// Subtract that many days to get to Sunday
+	// Days since the configured first day of the week
+	dayOfWeek := (int(t.Weekday()) - int(cfg.weekStart) + 7) % 7
+	// Subtract that many days to get to the start of the week
 	weekStart := t.AddDate(0, 0, -dayOfWeek)
 	return truncateToDay(weekStart)
 }
@@ -251,6 +364,13 @@ func generateMarkdownFromTodos(todos []Todo, includeBackups bool) string {
 
 	// Summary
 	sb.WriteString(fmt.Sprintf("**Total completed todos:** %d\n\n", len(todos)))
+	if counts := countTags(todos); len(counts) > 0 {
+		byTag := make([]string, len(counts))
+		for i, c := range counts {
+			byTag[i] = fmt.Sprintf("#%s (%d)", c.tag, c.count)
+		}
+		sb.WriteString(fmt.Sprintf("**By tag:** %s\n\n", strings.Join(byTag, ", ")))
+	}
 	sb.WriteString("---\n\n")
 
 	// Group todos by week
@@ -264,12 +384,19 @@ func generateMarkdownFromTodos(todos []Todo, includeBackups bool) string {
 			todoCount += len(day.Todos)
 		}
 		sb.WriteString(fmt.Sprintf("## Week of %s\n\n", weekRange))
-		sb.WriteString(fmt.Sprintf("*%d todos completed this week*\n\n", todoCount))
+		if spent := weekTimeSpent(week, time.Now()); spent > 0 {
+			sb.WriteString(fmt.Sprintf("*%d todos completed this week, %s logged*\n\n", todoCount, formatDuration(spent)))
+		} else {
+			sb.WriteString(fmt.Sprintf("*%d todos completed this week*\n\n", todoCount))
+		}
 
 		// Days within the week
 		for _, day := range week.Days {
 			// Day header
 			dayHeader := formatDayHeader(day.Date)
+			if spent := timeSpentOn(day.Todos, time.Now()); spent > 0 {
+				dayHeader += fmt.Sprintf(" (%s logged)", formatDuration(spent))
+			}
 			sb.WriteString(fmt.Sprintf("### %s\n\n", dayHeader))
 
 			// Todos for this day
@@ -278,17 +405,37 @@ func generateMarkdownFromTodos(todos []Todo, includeBackups bool) string {
 				timeStr := todo.CompletedAt.Format("3:04 PM")
 
 				// Todo item
-				sb.WriteString(fmt.Sprintf("- **%s** _%s_\n", todo.Text, timeStr))
+				line := fmt.Sprintf("- **%s** _%s_", todo.Text, timeStr)
+				if spent := timeSpent(todo, time.Now()); spent > 0 {
+					line += " (" + formatDuration(spent) + ")"
+				}
+				if extra := extraTags(todo); len(extra) > 0 {
+					line += " " + formatTags(extra)
+				}
+				sb.WriteString(line + "\n")
 
 				// Complete note (shown first)
 				if todo.CompleteNote != "" {
 					sb.WriteString(fmt.Sprintf("  - ✓ %s\n", todo.CompleteNote))
 				}
 
+				// Checklist as task list items
+				for _, item := range todo.Checklist {
+					box := "[ ]"
+					if item.Done {
+						box = "[x]"
+					}
+					sb.WriteString(fmt.Sprintf("  - %s %s\n", box, item.Text))
+				}
+
 				// Updates
 				if len(todo.Updates) > 0 {
 					for _, update := range todo.Updates {
-						sb.WriteString(fmt.Sprintf("  - %s\n", update))
+						line := "  - " + update.Text
+						if stamp := formatUpdateStamp(update, "Jan 2, 3:04 PM"); stamp != "" {
+							line += " _(" + stamp + ")_"
+						}
+						sb.WriteString(line + "\n")
 					}
 				}
 			}
@@ -155,14 +155,78 @@ func TestUpdateCompletedTodo(t *testing.T) {
 
 	// Update the first todo
 	m.updateCompletedTodo(func(t *Todo) {
-		t.Updates = []string{"updated update"}
+		t.Updates = textUpdates("updated update")
 	})
 
-	if len(m.completed[0].Updates) != 1 || m.completed[0].Updates[0] != "updated update" {
+	if len(m.completed[0].Updates) != 1 || m.completed[0].Updates[0].Text != "updated update" {
 		t.Errorf("updateCompletedTodo() did not update updates, got %v", m.completed[0].Updates)
 	}
 }
 
+func TestUpdateCompletedTodoDuplicateText(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-1 * time.Hour)
+	// Legacy plain-text imports can share both text and creation time
+	m := Model{
+		completed: []Todo{
+			{ID: "first", Text: "Same", CreatedAt: now, CompletedAt: &earlier},
+			{ID: "second", Text: "Same", CreatedAt: now, CompletedAt: &now},
+		},
+		cursor: 0,
+	}
+	m.updateDisplayedCompleted()
+
+	// The most recently completed todo is displayed first
+	m.updateCompletedTodo(func(t *Todo) {
+		t.CompleteNote = "done"
+	})
+
+	if m.completed[1].CompleteNote != "done" {
+		t.Error("updateCompletedTodo() should update the todo under the cursor")
+	}
+	if m.completed[0].CompleteNote != "" {
+		t.Error("updateCompletedTodo() should not update a todo with the same text")
+	}
+}
+
+func TestSameTodo(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		a        Todo
+		b        Todo
+		expected bool
+	}{
+		{"same ID", Todo{ID: "a", Text: "x", CreatedAt: now}, Todo{ID: "a", Text: "renamed", CreatedAt: now}, true},
+		{"different ID same text", Todo{ID: "a", Text: "x", CreatedAt: now}, Todo{ID: "b", Text: "x", CreatedAt: now}, false},
+		{"one ID missing", Todo{ID: "a", Text: "x", CreatedAt: now}, Todo{Text: "x", CreatedAt: now}, false},
+		{"no IDs same text and time", Todo{Text: "x", CreatedAt: now}, Todo{Text: "x", CreatedAt: now}, true},
+		{"no IDs different time", Todo{Text: "x", CreatedAt: now}, Todo{Text: "x", CreatedAt: now.Add(time.Second)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameTodo(tt.a, tt.b); got != tt.expected {
+				t.Errorf("sameTodo() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewTodoID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := newTodoID()
+		if len(id) != 16 {
+			t.Errorf("newTodoID() = %q, want 16 hex characters", id)
+		}
+		if seen[id] {
+			t.Errorf("newTodoID() returned duplicate %q", id)
+		}
+		seen[id] = true
+	}
+}
+
 func TestSwapTodos(t *testing.T) {
 	now := time.Now()
 	todos := []Todo{
@@ -568,7 +632,7 @@ func TestGenerateMarkdownFromTodos(t *testing.T) {
 					Text:        "Task with notes",
 					CreatedAt:   now,
 					CompletedAt: &todo1,
-					Updates:     []string{"Note 1", "Note 2"},
+					Updates:     textUpdates("Note 1", "Note 2"),
 				},
 			},
 			wantContains: []string{
@@ -589,6 +653,21 @@ func TestGenerateMarkdownFromTodos(t *testing.T) {
 				"Second Task",
 			},
 		},
+		{
+			name: "todos with tags",
+			todos: []Todo{
+				{Text: "Deploy #infra", Tags: []string{"infra", "q3"}, CreatedAt: now, CompletedAt: &todo1},
+				{Text: "Rotate keys", Tags: []string{"infra"}, CreatedAt: now, CompletedAt: &todo2},
+			},
+			wantContains: []string{
+				"**By tag:** #infra (2), #q3 (1)",
+				"- **Deploy #infra** _",
+				"#q3\n",
+			},
+			wantNotContains: []string{
+				"#infra #q3",
+			},
+		},
 	}
 
 	for _, tt := range tests {
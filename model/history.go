@@ -0,0 +1,224 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backupCountPattern matches the todo count at the end of a backup file name
+var backupCountPattern = regexp.MustCompile(`_(\d+)\.txt$`)
+
+// countArchived returns how many todos the backup files hold, going by the count in
+// their names so the files needn't be read
+func countArchived() int {
+	files, err := findBackupFiles()
+	if err != nil {
+		return 0
+	}
+	total := 0
+	for _, file := range files {
+		if match := backupCountPattern.FindStringSubmatch(filepath.Base(file)); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			total += n
+		}
+	}
+	return total
+}
+
+// archivedCount returns how many todos the backup files hold, counting them the first
+// time it's needed after they change
+func (m *Model) archivedCount() int {
+	if !m.archivedCounted {
+		m.archivedTotal = countArchived()
+		m.archivedCounted = true
+	}
+	return m.archivedTotal
+}
+
+// loadArchive reads the backup files into the completed history, the first time a page
+// beyond the Completed file is needed
+func (m *Model) loadArchive() {
+	if m.archiveLoaded {
+		return
+	}
+	m.archiveLoaded = true
+	m.archive = nil
+	files, err := findBackupFiles()
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		m.archive = append(m.archive, loadTodos(file)...)
+	}
+}
+
+// forgetArchive drops the loaded backups so they're read again when next needed, after
+// a backup file is written or removed
+func (m *Model) forgetArchive() {
+	m.archive = nil
+	m.archiveLoaded = false
+	m.archivedCounted = false
+}
+
+// completedHistory returns the completed todos matching the tag filter, most recent
+// first, including the backups once they're loaded
+func (m *Model) completedHistory() []Todo {
+	var filtered []Todo
+	for _, todos := range [][]Todo{m.completed, m.archive} {
+		for _, todo := range todos {
+			if m.matchesTagFilter(todo) {
+				filtered = append(filtered, todo)
+			}
+		}
+	}
+	return sortByCompletedDesc(filtered)
+}
+
+// archivedLockedKeys are the actions that would change the selected todo, which aren't
+// allowed on archived todos
+var archivedLockedKeys = map[string]bool{
+	"J": true, "K": true, "t": true, "d": true, "n": true, "u": true, "c": true,
+	"x": true, "r": true, "b": true, "<": true, ">": true, "C": true, "m": true,
+	"T": true, "D": true, "R": true, "+": true, "=": true, "-": true,
+}
+
+// isArchived reports whether todo comes from a backup file rather than the Completed
+// file; archived todos can be read but not changed
+func (m *Model) isArchived(todo Todo) bool {
+	return m.currentView == viewCompleted && findTodoIndex(m.completed, todo) < 0
+}
+
+// selectedArchived reports whether the selected todo comes from a backup file
+func (m *Model) selectedArchived() bool {
+	return m.currentView == viewCompleted && m.cursor < len(m.displayedCompleted) &&
+		m.isArchived(m.displayedCompleted[m.cursor])
+}
+
+// completedPages returns how many pages the completed history fills
+func (m *Model) completedPages() int {
+	return max(1, (m.completedTotal+cfg.completedLimit-1)/cfg.completedLimit)
+}
+
+// hasOlderPage reports whether there are older completed todos to page to, counting
+// backups that haven't been loaded yet
+func (m *Model) hasOlderPage() bool {
+	return m.completedPage < m.completedPages()-1 || (!m.archiveLoaded && m.archivedCount() > 0)
+}
+
+// pageCompleted moves delta pages through the completed history, older for positive
+// delta, loading the backups once the Completed file runs out
+func (m *Model) pageCompleted(delta int) {
+	if delta > 0 && !m.hasOlderPage() {
+		m.message = "No older completed todos"
+		return
+	}
+	if delta < 0 && m.completedPage == 0 {
+		m.message = "Already showing the most recent todos"
+		return
+	}
+	if delta > 0 && m.completedPage+delta >= m.completedPages()-1 {
+		m.loadArchive()
+	}
+	page := m.completedPage
+	m.completedPage += delta
+	m.updateDisplayedCompleted()
+	if m.completedPage == page {
+		// The backups held nothing matching the tag filter
+		m.message = "No older completed todos"
+		return
+	}
+	m.cursor = 0
+	m.message = ""
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+}
+
+// completedRange describes the page shown, e.g. "Showing 11–20 of 842". Until the
+// backups are loaded their todos are counted from their file names, or left out when
+// a tag filter needs their tags.
+func (m *Model) completedRange() string {
+	if m.completedTotal == 0 {
+		return ""
+	}
+	first := m.completedPage*cfg.completedLimit + 1
+	last := first + len(m.displayedCompleted) - 1
+	total := m.completedTotal
+	more := ""
+	if !m.archiveLoaded {
+		if archived := m.archivedCount(); archived > 0 && len(m.tagFilter) == 0 {
+			total += archived
+		} else if archived > 0 {
+			more = " in the Completed file; ] pages into backups"
+		}
+	}
+	return fmt.Sprintf("Showing %d–%d of %d%s", first, last, total, more)
+}
+
+// parseJumpDate parses a date to jump to in the completed history: a day
+// (2026-07-01), a month (2026-07 or jul 2026), a year (2025) or a time ago (10d, 3w,
+// 2m or 1y). It returns the end of that period.
+func parseJumpDate(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("enter a date to jump to")
+	}
+	loc := now.Location()
+	if t, err := time.ParseInLocation("2006-01-02", input, loc); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	// Month names only parse capitalised
+	month := strings.ToUpper(input[:1]) + input[1:]
+	for _, layout := range []string{"2006-01", "Jan 2006", "January 2006"} {
+		if t, err := time.ParseInLocation(layout, month, loc); err == nil {
+			return t.AddDate(0, 1, 0), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006", input, loc); err == nil {
+		return t.AddDate(1, 0, 0), nil
+	}
+
+	input = strings.TrimSuffix(strings.TrimPrefix(input, "-"), " ago")
+	if len(input) >= 2 {
+		if n, err := strconv.Atoi(input[:len(input)-1]); err == nil && n >= 0 {
+			today := truncateToDay(now).AddDate(0, 0, 1)
+			switch input[len(input)-1] {
+			case 'd':
+				return today.AddDate(0, 0, -n), nil
+			case 'w':
+				return today.AddDate(0, 0, -7*n), nil
+			case 'm':
+				return today.AddDate(0, -n, 0), nil
+			case 'y':
+				return today.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a date (try 2026-07-01, 2026-07, jul 2026, 2025 or 3w)", input)
+}
+
+// jumpToDate shows the most recent todo completed before the end of the period in
+// input, loading the backups to search all of history
+func (m *Model) jumpToDate(input string) {
+	end, err := parseJumpDate(input, time.Now())
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.loadArchive()
+	history := m.completedHistory()
+	for i, todo := range history {
+		if todo.CompletedAt != nil && todo.CompletedAt.Before(end) {
+			m.completedPage = i / cfg.completedLimit
+			m.updateDisplayedCompleted()
+			m.cursor = i % cfg.completedLimit
+			m.message = "Jumped to " + todo.CompletedAt.Format("Jan 2, 2006")
+			return
+		}
+	}
+	m.message = fmt.Sprintf("Nothing was completed before %s", end.Format("Jan 2, 2006"))
+}
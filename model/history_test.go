@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseJumpDate(t *testing.T) {
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.Local)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2026-01-15", day(2026, 1, 16)},
+		{"2025-12", day(2026, 1, 1)},
+		{"Jul 2025", day(2025, 8, 1)},
+		{"september 2025", day(2025, 10, 1)},
+		{"2025", day(2026, 1, 1)},
+		{"0d", day(2026, 3, 12)},
+		{"10d", day(2026, 3, 2)},
+		{"2w ago", day(2026, 2, 26)},
+		{"-3m", day(2025, 12, 12)},
+		{"1y", day(2025, 3, 12)},
+	}
+	for _, tt := range tests {
+		got, err := parseJumpDate(tt.input, now)
+		if err != nil || !got.Equal(tt.expected) {
+			t.Errorf("parseJumpDate(%q) = %v, %v, want %v", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"", "last quarter", "3x", "2026-13-01"} {
+		if _, err := parseJumpDate(input, now); err == nil {
+			t.Errorf("parseJumpDate(%q) should fail", input)
+		}
+	}
+}
+
+func TestCompletedPaging(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// 25 todos completed over the last 25 days, and 5 older ones in a backup
+	now := time.Now()
+	completedDaysAgo := func(days int) Todo {
+		completed := now.AddDate(0, 0, -days)
+		return Todo{ID: fmt.Sprintf("t%d", days), Text: fmt.Sprintf("Done %d days ago", days), CompletedAt: &completed}
+	}
+	var completed, archived []Todo
+	for days := range 25 {
+		completed = append(completed, completedDaysAgo(days))
+	}
+	for days := 100; days < 105; days++ {
+		archived = append(archived, completedDaysAgo(days))
+	}
+	saveTodos("todo_completed_backup_2026-01-05_5.txt", archived)
+
+	m := Model{currentView: viewCompleted, completed: completed}
+	m.updateDisplayedCompleted()
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+
+	if got := m.completedRange(); got != "Showing 1–10 of 30" {
+		t.Errorf("completedRange() = %q, want the backup counted from its name", got)
+	}
+
+	// The count is kept until a backup file is written or removed
+	extra := "todo_completed_backup_2026-01-06_3.txt"
+	saveTodos(extra, nil)
+	if got := m.completedRange(); got != "Showing 1–10 of 30" {
+		t.Errorf("completedRange() = %q, want the cached count", got)
+	}
+	m.forgetArchive()
+	m.updateDisplayedCompleted()
+	if got := m.completedRange(); got != "Showing 1–10 of 33" {
+		t.Errorf("completedRange() = %q, want the new backup counted", got)
+	}
+	os.Remove(extra)
+	m.forgetArchive()
+	m.updateDisplayedCompleted()
+
+	if len(m.displayedCompleted) != 10 || m.displayedCompleted[0].ID != "t0" {
+		t.Fatalf("first page = %+v, want the 10 most recent", m.displayedCompleted)
+	}
+
+	press("]")
+	if m.archiveLoaded || m.displayedCompleted[0].ID != "t10" {
+		t.Errorf("second page starts with %s, want t10 without loading backups", m.displayedCompleted[0].ID)
+	}
+	press("]")
+	if !m.archiveLoaded || len(m.displayedCompleted) != 10 || m.displayedCompleted[9].ID != "t104" {
+		t.Fatalf("last page = %+v, want the backup loaded", m.displayedCompleted)
+	}
+	if view := m.View(); !strings.Contains(view, "Showing 21–30 of 30") || !strings.Contains(view, "(archived)") {
+		t.Errorf("View should show the range and label archived todos:\n%s", view)
+	}
+	press("]")
+	if m.completedPage != 2 || m.message != "No older completed todos" {
+		t.Errorf("page = %d, message = %q, want to stay on the last page", m.completedPage, m.message)
+	}
+
+	// Archived todos can't be changed
+	m.cursor = 9
+	press("d")
+	if m.confirmingDelete || m.message != "Archived todos can't be changed" {
+		t.Errorf("deleting an archived todo should be refused, message = %q", m.message)
+	}
+
+	press("[")
+	press("[")
+	press("[")
+	if m.completedPage != 0 || m.message != "Already showing the most recent todos" {
+		t.Errorf("page = %d, message = %q, want the first page", m.completedPage, m.message)
+	}
+
+	// Jump to the most recent todo completed on or before a date
+	press("@")
+	for _, r := range "2w" {
+		press(string(r))
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.jumpingToDate || m.completedPage != 1 || m.displayedCompleted[m.cursor].ID != "t14" {
+		t.Errorf("page %d, cursor %d, want t14 selected after jumping back two weeks", m.completedPage, m.cursor)
+	}
+}
+
+func TestCompletedPagingWithoutBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	m := Model{currentView: viewCompleted, completed: []Todo{{Text: "Only one", CompletedAt: &now}}}
+	m.updateDisplayedCompleted()
+
+	m.pageCompleted(1)
+	if m.completedPage != 0 || m.message != "No older completed todos" {
+		t.Errorf("page = %d, message = %q, want no older page", m.completedPage, m.message)
+	}
+	if got := m.completedRange(); got != "Showing 1–1 of 1" {
+		t.Errorf("completedRange() = %q", got)
+	}
+}
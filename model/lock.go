@@ -0,0 +1,246 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	lockFile = "todo.lock"
+
+	// lockRefreshInterval is how often the app touches its lock to show it's still running
+	lockRefreshInterval = time.Minute
+
+	// lockStaleAfter is how long a lock can go untouched before it's taken to be left
+	// behind by a crashed app or a machine that went to sleep
+	lockStaleAfter = 5 * time.Minute
+)
+
+// lockOwner identifies the process holding the lock on the todo files
+type lockOwner struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// String describes the owner for messages, e.g. "pid 4242 on laptop since Jan 2, 15:04"
+func (o lockOwner) String() string {
+	return fmt.Sprintf("pid %d on %s since %s", o.PID, o.Host, o.Started.Format("Jan 2, 15:04"))
+}
+
+// LockedError is returned when another process holds the lock on the todo files
+type LockedError struct {
+	Owner lockOwner
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("the todo files are in use by another copy of the app (%s); remove %s if it isn't running", e.Owner, lockFile)
+}
+
+// hostname returns the machine's name, or "unknown" if it can't be read
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
+// isCurrentProcess reports whether the owner is this process
+func (o lockOwner) isCurrentProcess() bool {
+	return o.PID == os.Getpid() && o.Host == hostname()
+}
+
+// processAlive reports whether a process with pid is running on this machine
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
+
+// readLock returns the lock's owner and whether the lock is stale: left by a process
+// on this machine that has exited, untouched for lockStaleAfter, or unreadable
+func readLock() (lockOwner, bool, error) {
+	return readLockFile(lockFile)
+}
+
+// readLockFile is readLock for a lock file at path
+func readLockFile(path string) (lockOwner, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return lockOwner{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lockOwner{}, false, err
+	}
+	var owner lockOwner
+	if err := json.Unmarshal(data, &owner); err != nil {
+		return owner, true, nil
+	}
+	stale := time.Since(info.ModTime()) > lockStaleAfter ||
+		(owner.Host == hostname() && !owner.isCurrentProcess() && !processAlive(owner.PID))
+	return owner, stale, nil
+}
+
+// sameOwner reports whether two lock owners are the same process
+func sameOwner(a, b lockOwner) bool {
+	return a.PID == b.PID && a.Host == b.Host && a.Started.Equal(b.Started)
+}
+
+// acquireLock takes the advisory lock on the todo files in the current directory,
+// taking over a stale lock. It returns a *LockedError if another process holds it.
+func acquireLock() error {
+	for range 2 {
+		file, err := os.OpenFile(lockFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			owner := lockOwner{PID: os.Getpid(), Host: hostname(), Started: time.Now()}
+			err = json.NewEncoder(file).Encode(owner)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockFile)
+				return fmt.Errorf("writing %s: %w", lockFile, err)
+			}
+
+			// Another process taking over a lock it judged stale at the same time could
+			// have replaced this one, so check it's still ours
+			if current, _, err := readLock(); err != nil || !current.isCurrentProcess() {
+				return &LockedError{current}
+			}
+			return nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("creating %s: %w", lockFile, err)
+		}
+
+		owner, stale, err := readLock()
+		if errors.Is(err, os.ErrNotExist) {
+			// Released since the attempt to create it
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", lockFile, err)
+		}
+		if owner.isCurrentProcess() {
+			return nil
+		}
+		if !stale {
+			return &LockedError{owner}
+		}
+		if err := moveStaleLock(owner); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("couldn't take %s, another process keeps replacing it", lockFile)
+}
+
+// moveStaleLock gets the stale lock held by owner out of the way. It's renamed aside
+// rather than removed, so if another process replaced it with a fresh lock since it
+// was read, that lock can be put back instead of being deleted.
+func moveStaleLock(owner lockOwner) error {
+	aside := fmt.Sprintf("%s.%d.stale", lockFile, os.Getpid())
+	if err := os.Rename(lockFile, aside); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Already taken over by another process
+			return nil
+		}
+		return fmt.Errorf("moving stale %s: %w", lockFile, err)
+	}
+	defer os.Remove(aside)
+
+	moved, stale, err := readLockFile(aside)
+	if err == nil && !stale && !sameOwner(moved, owner) {
+		// Put the other process's lock back, unless yet another lock has replaced it
+		os.Link(aside, lockFile)
+		return &LockedError{moved}
+	}
+	return nil
+}
+
+// refreshLock touches the lock so it isn't taken for stale, taking it again if it was
+// removed. It returns a *LockedError if another process has taken it over.
+func refreshLock() error {
+	owner, _, err := readLock()
+	if errors.Is(err, os.ErrNotExist) {
+		return acquireLock()
+	}
+	if err != nil {
+		return err
+	}
+	if !owner.isCurrentProcess() {
+		return &LockedError{owner}
+	}
+	now := time.Now()
+	return os.Chtimes(lockFile, now, now)
+}
+
+// ReleaseLock removes the lock on the todo files if this process holds it
+func ReleaseLock() {
+	if owner, _, err := readLock(); err == nil && owner.isCurrentProcess() {
+		os.Remove(lockFile)
+	}
+}
+
+// mutatingKeys are the actions that change todos without a todo selected, which
+// aren't allowed while read-only along with archivedLockedKeys
+var mutatingKeys = map[string]bool{
+	"a": true, "A": true, "B": true, "M": true, "ctrl+z": true, "U": true, "ctrl+r": true,
+}
+
+// readOnlyMessage explains why a change was refused
+func (m *Model) readOnlyMessage() string {
+	return fmt.Sprintf("Read-only: another copy of the app (%s) is editing these todos", m.lockOwner)
+}
+
+// checkLock keeps the lock fresh while it's held, switching to read-only if another
+// process took it over, and retries it while read-only so editing resumes once the
+// other copy quits
+func (m *Model) checkLock(now time.Time) {
+	var locked *LockedError
+	if m.readOnly {
+		if err := acquireLock(); err == nil {
+			m.readOnly = false
+			m.holdsLock = true
+			m.lockRefreshed = now
+			m.fileWarning = "The other copy of the app has closed; editing is enabled"
+		} else if errors.As(err, &locked) {
+			m.lockOwner = locked.Owner
+		}
+		return
+	}
+	if !m.holdsLock || now.Sub(m.lockRefreshed) < lockRefreshInterval {
+		return
+	}
+	m.lockRefreshed = now
+	if err := refreshLock(); errors.As(err, &locked) {
+		m.readOnly = true
+		m.holdsLock = false
+		m.lockOwner = locked.Owner
+		m.leaveEditingModes()
+		m.fileWarning = "Another copy of the app took over the todo files; switched to read-only"
+	}
+}
+
+// leaveEditingModes closes every prompt and mode whose keys change todos, as their
+// keys aren't checked against the lock
+func (m *Model) leaveEditingModes() {
+	m.adding, m.addingToTop, m.newTodo = false, false, ""
+	m.editingUpdate, m.newUpdate = false, ""
+	m.editingCompleteNote, m.newCompleteNote = false, ""
+	m.renamingTodo, m.newTodoName = false, ""
+	m.confirmingDelete, m.confirmingDeleteUpdate, m.confirmingMerge = false, false, false
+	m.navigatingUpdates = false
+	m.editingTags, m.newTags = false, ""
+	m.editingDue, m.newDue = false, ""
+	m.editingRecurrence, m.newRecurrence = false, ""
+	m.navigatingChecklist, m.editingChecklist, m.renamingChecklistItem, m.newChecklistItem = false, false, false, ""
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeLock writes a lock file held by owner, last touched at modTime
+func writeLock(t *testing.T, owner lockOwner, modTime time.Time) {
+	t.Helper()
+	data, _ := json.Marshal(owner)
+	if err := os.WriteFile(lockFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(lockFile, modTime, modTime)
+}
+
+func TestAcquireLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	elsewhere := lockOwner{PID: 4242, Host: "other-machine", Started: now}
+	tests := []struct {
+		name   string
+		setup  func()
+		locked bool
+	}{
+		{name: "no lock", setup: func() {}},
+		{name: "held by this process", setup: func() { writeLock(t, lockOwner{PID: os.Getpid(), Host: hostname()}, now) }},
+		{name: "held on another machine", setup: func() { writeLock(t, elsewhere, now) }, locked: true},
+		{name: "left untouched on another machine", setup: func() { writeLock(t, elsewhere, now.Add(-2*lockStaleAfter)) }},
+		{name: "left by an exited process", setup: func() { writeLock(t, lockOwner{PID: 1 << 30, Host: hostname()}, now) }},
+		{name: "unreadable", setup: func() { os.WriteFile(lockFile, []byte("{"), 0644) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(lockFile)
+			tt.setup()
+			err := acquireLock()
+			var locked *LockedError
+			if tt.locked {
+				if !errors.As(err, &locked) || locked.Owner.Host != "other-machine" {
+					t.Fatalf("acquireLock() = %v, want a LockedError naming the owner", err)
+				}
+				if !strings.Contains(err.Error(), "pid 4242 on other-machine") {
+					t.Errorf("error = %q, want the owner described", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("acquireLock() = %v", err)
+			}
+			if owner, stale, err := readLock(); err != nil || stale || !owner.isCurrentProcess() {
+				t.Errorf("readLock() = %+v, %v, %v, want this process holding the lock", owner, stale, err)
+			}
+			ReleaseLock()
+			if _, err := os.Stat(lockFile); !os.IsNotExist(err) {
+				t.Error("ReleaseLock() should remove the lock")
+			}
+		})
+	}
+
+	// Another process's lock isn't released
+	writeLock(t, elsewhere, now)
+	ReleaseLock()
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Error("ReleaseLock() shouldn't remove another process's lock")
+	}
+
+	// A stale lock another process replaced since it was read is put back, not taken over
+	stale := lockOwner{PID: 1 << 30, Host: hostname()}
+	err := moveStaleLock(stale)
+	var locked *LockedError
+	if !errors.As(err, &locked) || locked.Owner.Host != "other-machine" {
+		t.Fatalf("moveStaleLock() = %v, want a LockedError naming the new owner", err)
+	}
+	if owner, _, err := readLock(); err != nil || !sameOwner(owner, elsewhere) {
+		t.Errorf("readLock() = %+v, %v, want the other process's lock put back", owner, err)
+	}
+	if entries, _ := os.ReadDir("."); len(entries) != 1 {
+		t.Errorf("got %d files, want only the lock left", len(entries))
+	}
+}
+
+func TestReadOnlyWhenLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Shared"}})
+	writeLock(t, lockOwner{PID: 4242, Host: "other-machine", Started: time.Now()}, time.Now())
+
+	m := InitialModel()
+	if !m.readOnly || m.holdsLock {
+		t.Fatal("InitialModel should open read-only when another copy holds the lock")
+	}
+	if _, err := os.Stat(backupDir); !os.IsNotExist(err) {
+		t.Error("InitialModel shouldn't back up while another copy holds the lock")
+	}
+	for _, key := range []string{"a", "x", "d"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		if m.adding || m.confirmingDelete || len(m.completed) != 0 || !strings.HasPrefix(m.message, "Read-only") {
+			t.Errorf("%q should be refused while read-only, message = %q", key, m.message)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "READ-ONLY") || !strings.Contains(view, "pid 4242 on other-machine") {
+		t.Errorf("View should show who holds the lock:\n%s", view)
+	}
+
+	// Editing resumes once the other copy quits
+	os.Remove(lockFile)
+	updated, _ := m.Update(fileCheckMsg{})
+	m = updated.(Model)
+	if m.readOnly || !m.holdsLock {
+		t.Fatal("the lock should be taken once it's free")
+	}
+
+	// And stops again if another copy takes the lock over
+	m.ready = []Todo{{ID: "1", Text: "Shared", Checklist: []ChecklistItem{{Text: "Step"}}}}
+	m.navigatingChecklist = true
+	writeLock(t, lockOwner{PID: 4242, Host: "other-machine", Started: time.Now()}, time.Now())
+	m.checkLock(time.Now().Add(lockRefreshInterval))
+	if !m.readOnly || !strings.Contains(m.fileWarning, "switched to read-only") {
+		t.Errorf("readOnly = %v, warning = %q, want read-only after losing the lock", m.readOnly, m.fileWarning)
+	}
+
+	// Modes whose keys change todos are closed, so space can't tick the checklist
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(Model)
+	if m.navigatingChecklist || m.ready[0].Checklist[0].Done {
+		t.Errorf("checklist = %+v, want the checklist mode closed on losing the lock", m.ready[0].Checklist)
+	}
+}
+
+func TestCLIHonoursLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	writeLock(t, lockOwner{PID: 4242, Host: "other-machine", Started: time.Now()}, time.Now())
+	var out bytes.Buffer
+	var locked *LockedError
+	if err := RunCommand([]string{"add", "Blocked"}, &out); !errors.As(err, &locked) {
+		t.Fatalf("add = %v, want a LockedError", err)
+	}
+	if len(loadTodos(readyFile)) != 0 {
+		t.Error("nothing should be saved while another copy holds the lock")
+	}
+	if err := RunCommand([]string{"list"}, &out); err != nil {
+		t.Errorf("list should work while locked: %v", err)
+	}
+
+	os.Remove(lockFile)
+	if err := RunCommand([]string{"add", "Allowed"}, &out); err != nil {
+		t.Fatalf("add = %v", err)
+	}
+	if _, err := os.Stat(lockFile); !os.IsNotExist(err) {
+		t.Error("the CLI should release the lock when done")
+	}
+}
@@ -0,0 +1,368 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// syncConflict is a todo file along with the conflicted copies a sync tool such as
+// Dropbox left next to it
+type syncConflict struct {
+	file   string
+	copies []string
+}
+
+// conflictCopies returns the conflicted copies of filename, such as
+// "todo_ready (laptop's conflicted copy 2026-03-01).txt" or
+// "todo_ready.sync-conflict-20260301-101500-ABCDEFG.txt". Copies of other files that
+// share the name's start, such as the completed backups, aren't included.
+func conflictCopies(filename string) []string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	matches, err := filepath.Glob(base + "*" + ext)
+	if err != nil {
+		return nil
+	}
+	var copies []string
+	for _, match := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(match, base), ext)
+		dropbox := strings.HasPrefix(suffix, " (") && strings.HasSuffix(suffix, ")") &&
+			strings.Contains(strings.ToLower(suffix), "conflict")
+		if dropbox || strings.HasPrefix(suffix, ".sync-conflict-") {
+			copies = append(copies, match)
+		}
+	}
+	return copies
+}
+
+// backupPath returns where to keep path in backupDir, adding the time to the name if
+// an earlier copy with the same name is already kept there
+func backupPath(path string) string {
+	target := filepath.Join(backupDir, path)
+	if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
+		return target
+	}
+	ext := filepath.Ext(path)
+	return filepath.Join(backupDir, strings.TrimSuffix(path, ext)+time.Now().Format(" (moved 2006-01-02_150405.000)")+ext)
+}
+
+// findSyncConflicts returns each column's file that has conflicted copies
+func findSyncConflicts() []syncConflict {
+	var conflicts []syncConflict
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		if copies := conflictCopies(filename); len(copies) > 0 {
+			conflicts = append(conflicts, syncConflict{filename, copies})
+		}
+	}
+	return conflicts
+}
+
+// todoFields returns the todo's JSON fields, so fields can be merged one at a time
+func todoFields(todo Todo) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if data, err := json.Marshal(todo); err == nil {
+		json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// mergeTodoFields merges two changed versions of a todo field by field: a field changed
+// on one side only takes that change. Where both changed a field, updates from both
+// are kept, a completed todo stays completed at the earlier time, and otherwise ours
+// is kept and the field is listed as a conflict. With no base (nil) neither side can
+// be told to be the newer, so every field that differs is changed on both.
+func mergeTodoFields(base *Todo, ours, theirs Todo) (Todo, []string) {
+	var b map[string]json.RawMessage
+	var baseUpdates []TodoUpdate
+	if base != nil {
+		b, baseUpdates = todoFields(*base), base.Updates
+	}
+	o, t := todoFields(ours), todoFields(theirs)
+	keys := map[string]bool{}
+	for _, fields := range []map[string]json.RawMessage{b, o, t} {
+		for key := range fields {
+			keys[key] = true
+		}
+	}
+
+	merged := map[string]json.RawMessage{}
+	var conflicts []string
+	for key := range keys {
+		value := o[key]
+		switch {
+		case bytes.Equal(o[key], t[key]):
+		case base != nil && bytes.Equal(t[key], b[key]):
+		case base != nil && bytes.Equal(o[key], b[key]):
+			value = t[key]
+		case key == "updates":
+			value, _ = json.Marshal(mergeUpdates(baseUpdates, ours.Updates, theirs.Updates))
+		case key == "completed_at" && (base != nil || ours.CompletedAt != nil && theirs.CompletedAt != nil):
+			if theirs.CompletedAt != nil && (ours.CompletedAt == nil || theirs.CompletedAt.Before(*ours.CompletedAt)) {
+				value = t[key]
+			}
+		default:
+			conflicts = append(conflicts, strings.ReplaceAll(key, "_", " "))
+		}
+		if value != nil {
+			merged[key] = value
+		}
+	}
+	sort.Strings(conflicts)
+
+	var todo Todo
+	data, err := json.Marshal(merged)
+	if err != nil || json.Unmarshal(data, &todo) != nil {
+		return ours, conflicts
+	}
+	return todo, conflicts
+}
+
+// updateKey identifies an update across copies of a todo by when it was written, or
+// by its text for updates saved before they were timestamped
+func updateKey(update TodoUpdate) string {
+	if update.CreatedAt.IsZero() {
+		return "text:" + update.Text
+	}
+	return update.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// mergeUpdates keeps the updates from both ours and theirs, most recent first. An update
+// removed on either side since base is dropped, and one edited on both sides keeps the
+// later edit.
+func mergeUpdates(base, ours, theirs []TodoUpdate) []TodoUpdate {
+	inBase, inOurs := map[string]bool{}, map[string]bool{}
+	theirsByKey := map[string]TodoUpdate{}
+	for _, update := range base {
+		inBase[updateKey(update)] = true
+	}
+	for _, update := range theirs {
+		theirsByKey[updateKey(update)] = update
+	}
+	editedAt := func(update TodoUpdate) time.Time {
+		if update.EditedAt == nil {
+			return time.Time{}
+		}
+		return *update.EditedAt
+	}
+
+	var merged []TodoUpdate
+	for _, update := range ours {
+		key := updateKey(update)
+		inOurs[key] = true
+		their, ok := theirsByKey[key]
+		if !ok && inBase[key] {
+			continue
+		}
+		if ok && editedAt(their).After(editedAt(update)) {
+			update = their
+		}
+		merged = append(merged, update)
+	}
+	for _, update := range theirs {
+		if key := updateKey(update); !inOurs[key] && !inBase[key] {
+			merged = append(merged, update)
+		}
+	}
+
+	// Untimestamped updates are the oldest, so they stay at the end
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].CreatedAt.After(merged[j].CreatedAt) })
+	return merged
+}
+
+// mergeBase returns the best common ancestor available for a todo file and its
+// conflicted copy at path: the todos in both that are also in the newest backup taken
+// before the copy was written. Backups taken since hold this machine's side, so they
+// aren't used; without an older one there's no base, and every field the copies
+// disagree on is a conflict. The backup may still be newer than the point the copies
+// diverged, so a todo missing from one copy is never taken as deleted, only kept.
+func mergeBase(filename, path string, ours, theirs []Todo) []Todo {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	snapshots, err := listSnapshots()
+	if err != nil {
+		return nil
+	}
+	i := 0
+	for i < len(snapshots) && !snapshots[i].taken.Before(info.ModTime()) {
+		i++
+	}
+	if i == len(snapshots) {
+		return nil
+	}
+
+	oursByID, theirsByID := todosByID(ours), todosByID(theirs)
+	var base []Todo
+	for _, todo := range readSnapshotTodos(snapshots[i], filename) {
+		_, inOurs := oursByID[todo.ID]
+		_, inTheirs := theirsByID[todo.ID]
+		if inOurs && inTheirs {
+			base = append(base, todo)
+		}
+	}
+	return base
+}
+
+// mergeSyncConflicts merges each conflicted copy into its todo file and moves the copy
+// into the backup directory. A todo that ends up in more than one column is kept in
+// the furthest one, so completing it in one copy wins over the other copy still
+// having it in an earlier column. It returns a line describing each file merged and
+// each conflict resolved.
+func mergeSyncConflicts(conflicts []syncConflict) ([]string, error) {
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var report []string
+	for _, conflict := range conflicts {
+		merged, _, err := readTodos(conflict.file)
+		if err != nil {
+			return report, err
+		}
+		var notes []string
+		for _, path := range conflict.copies {
+			theirs, _, err := readTodos(path)
+			if err != nil {
+				return report, err
+			}
+			var copyNotes []string
+			merged, copyNotes = mergeTodos(mergeBase(conflict.file, path, merged, theirs), merged, theirs)
+			notes = append(notes, copyNotes...)
+		}
+		if err := saveTodos(conflict.file, merged); err != nil {
+			return report, fmt.Errorf("saving %s: %w", conflict.file, err)
+		}
+		for _, path := range conflict.copies {
+			if err := os.Rename(path, backupPath(path)); err != nil {
+				return report, fmt.Errorf("moving %s to backup: %w", path, err)
+			}
+		}
+
+		report = append(report, fmt.Sprintf("Merged %d conflicted %s into %s", len(conflict.copies), pluralCopies(len(conflict.copies)), conflict.file))
+		for _, note := range notes {
+			report = append(report, "  conflict: "+note)
+		}
+		if len(notes) > 0 {
+			report = append(report, "  where both copies changed a field, "+conflict.file+"'s version was kept")
+		}
+	}
+
+	moved, err := dropDuplicatesAcrossColumns()
+	return append(report, moved...), err
+}
+
+// pluralCopies returns "copy" or "copies"
+func pluralCopies(n int) string {
+	if n == 1 {
+		return "copy"
+	}
+	return "copies"
+}
+
+// sameApartFromMove reports whether a and b hold the same data apart from when they
+// were completed, which moving a todo between columns changes
+func sameApartFromMove(a, b Todo) bool {
+	a.CompletedAt, b.CompletedAt = nil, nil
+	return equalTodo(a, b)
+}
+
+// dropDuplicatesAcrossColumns removes todos that are also in a later column, which
+// happens when a todo was moved on one side of a sync conflict. A todo that was also
+// changed in one of the columns is kept in both and reported as a conflict. The
+// files are backed up before anything is removed.
+func dropDuplicatesAcrossColumns() ([]string, error) {
+	var report []string
+	type placed struct {
+		v    view
+		todo Todo
+	}
+	seen := map[string]placed{}
+	var changed []string
+	kept := map[string][]Todo{}
+	order := columnOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		v := order[i]
+		filename := columnOf(v).file
+		todos, _, err := readTodos(filename)
+		if err != nil {
+			return report, err
+		}
+		kept[filename] = make([]Todo, 0, len(todos))
+		for _, todo := range todos {
+			later, ok := seen[todo.ID]
+			switch {
+			case !ok:
+				seen[todo.ID] = placed{v, todo}
+			case !sameApartFromMove(todo, later.todo):
+				report = append(report, fmt.Sprintf("  conflict: %q is in both %s and %s with different changes, so both were kept", todo.Text, viewName(v), viewName(later.v)))
+			default:
+				report = append(report, fmt.Sprintf("  removed %q from %s as it's also in %s", todo.Text, viewName(v), viewName(later.v)))
+				continue
+			}
+			kept[filename] = append(kept[filename], todo)
+		}
+		if len(kept[filename]) < len(todos) {
+			changed = append(changed, filename)
+		}
+	}
+
+	if len(changed) == 0 {
+		return report, nil
+	}
+	if err := createBackups(); err != nil {
+		return report, err
+	}
+	for _, filename := range changed {
+		if err := saveTodos(filename, kept[filename]); err != nil {
+			return report, fmt.Errorf("saving %s: %w", filename, err)
+		}
+	}
+	return report, nil
+}
+
+// mergeConflictCopies merges the conflicted copies found at startup and reloads the
+// lists, warning about any conflicts
+func (m *Model) mergeConflictCopies() {
+	report, err := mergeSyncConflicts(m.syncConflicts)
+	m.reloadColumns()
+	if err != nil {
+		m.message = "Merge failed: " + err.Error()
+		return
+	}
+
+	copies := 0
+	for _, conflict := range m.syncConflicts {
+		copies += len(conflict.copies)
+	}
+	m.syncConflicts = nil
+	m.message = fmt.Sprintf("Merged %d conflicted %s; the originals are in backup/", copies, pluralCopies(copies))
+	var notes []string
+	for _, line := range report {
+		if strings.HasPrefix(line, "  ") {
+			notes = append(notes, strings.TrimSpace(line))
+		}
+	}
+	m.fileWarning = strings.Join(notes, "; ")
+}
+
+// reloadColumns reads every column's file again after they were changed outside the
+// model. Undo history is dropped, since undoing would overwrite the changes.
+func (m *Model) reloadColumns() {
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		todos := loadTodos(filename)
+		m.setColumnTodos(filename, todos)
+		m.trackFile(filename, todos)
+	}
+	m.undoStack = nil
+	m.redoStack = nil
+}
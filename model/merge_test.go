@@ -0,0 +1,278 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConflictCopies(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	for _, name := range []string{
+		readyFile,
+		"todo_ready (laptop's conflicted copy 2026-03-01).txt",
+		"todo_ready.sync-conflict-20260301-101500-ABCDEFG.txt",
+		"todo_ready (1).txt",
+		"todo_completed_backup_2026-01-05_3.txt",
+		"todo_completed_backup_2026-01-05_3 (laptop's conflicted copy 2026-03-01).txt",
+		"todo_ready conflict notes.txt",
+	} {
+		os.WriteFile(name, nil, 0644)
+	}
+
+	expected := []string{
+		"todo_ready (laptop's conflicted copy 2026-03-01).txt",
+		"todo_ready.sync-conflict-20260301-101500-ABCDEFG.txt",
+	}
+	if got := conflictCopies(readyFile); !reflect.DeepEqual(got, expected) {
+		t.Errorf("conflictCopies() = %q, want %q", got, expected)
+	}
+	if got := findSyncConflicts(); len(got) != 1 || got[0].file != readyFile {
+		t.Errorf("findSyncConflicts() = %+v, want only the ready file, not the backup's copy", got)
+	}
+}
+
+func TestMergeTodoFields(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	early, late := created.Add(time.Hour), created.Add(2*time.Hour)
+	update := func(text string, at time.Time) TodoUpdate { return TodoUpdate{Text: text, CreatedAt: at} }
+	base := Todo{ID: "1", Text: "Call the bank", CreatedAt: created, Updates: []TodoUpdate{update("Left a message", created)}}
+	with := func(change func(*Todo)) Todo {
+		todo := base
+		todo.Updates = append([]TodoUpdate(nil), base.Updates...)
+		change(&todo)
+		return todo
+	}
+
+	tests := []struct {
+		name      string
+		ours      Todo
+		theirs    Todo
+		check     func(Todo) bool
+		conflicts []string
+	}{
+		{
+			name:   "different fields changed on each side",
+			ours:   with(func(t *Todo) { t.Priority = "P1" }),
+			theirs: with(func(t *Todo) { t.CompleteNote = "Sorted" }),
+			check:  func(t Todo) bool { return t.Priority == "P1" && t.CompleteNote == "Sorted" },
+		},
+		{
+			name:      "text changed on both sides keeps ours",
+			ours:      with(func(t *Todo) { t.Text = "Call the bank about the loan" }),
+			theirs:    with(func(t *Todo) { t.Text = "Call the bank today" }),
+			check:     func(t Todo) bool { return t.Text == "Call the bank about the loan" },
+			conflicts: []string{"text"},
+		},
+		{
+			name:   "updates added on both sides are kept",
+			ours:   with(func(t *Todo) { t.Updates = append([]TodoUpdate{update("Mine", early)}, t.Updates...) }),
+			theirs: with(func(t *Todo) { t.Updates = append([]TodoUpdate{update("Theirs", late)}, t.Updates...) }),
+			check: func(t Todo) bool {
+				return len(t.Updates) == 3 && t.Updates[0].Text == "Theirs" && t.Updates[1].Text == "Mine"
+			},
+		},
+		{
+			name:   "completed on both sides keeps the earlier time",
+			ours:   with(func(t *Todo) { t.CompletedAt = &late }),
+			theirs: with(func(t *Todo) { t.CompletedAt = &early }),
+			check:  func(t Todo) bool { return t.CompletedAt != nil && t.CompletedAt.Equal(early) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := mergeTodoFields(&base, tt.ours, tt.theirs)
+			if !tt.check(merged) || !reflect.DeepEqual(conflicts, tt.conflicts) {
+				t.Errorf("mergeTodoFields() = %+v with conflicts %q, want %q", merged, conflicts, tt.conflicts)
+			}
+		})
+	}
+
+	// Without a base, a field set on only one side is a conflict too
+	ours := with(func(t *Todo) { t.Updates = append(t.Updates, update("Mine", early)) })
+	theirs := with(func(t *Todo) { t.Priority = "P1"; t.CompletedAt = &late })
+	merged, conflicts := mergeTodoFields(nil, ours, theirs)
+	if merged.Priority != "" || merged.CompletedAt != nil || len(merged.Updates) != 2 {
+		t.Errorf("mergeTodoFields(nil) = %+v, want ours kept with both sides' updates", merged)
+	}
+	if !reflect.DeepEqual(conflicts, []string{"completed at", "priority"}) {
+		t.Errorf("mergeTodoFields(nil) conflicts = %q, want completed at and priority", conflicts)
+	}
+}
+
+func TestRunCommandMerge(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	completedAt := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	base := []Todo{{ID: "1", Text: "Pay rent"}, {ID: "2", Text: "Book flights"}}
+	saveTodos(readyFile, base)
+	createBackups()
+
+	// This machine added a todo; the laptop renamed one and completed the other
+	saveTodos(readyFile, append(base, Todo{ID: "3", Text: "Renew passport"}))
+	conflicted := "todo_ready (laptop's conflicted copy 2026-03-01).txt"
+	saveTodos(conflicted, []Todo{{ID: "1", Text: "Pay rent and council tax"}})
+	saveTodos(completedFile, []Todo{{ID: "2", Text: "Book flights", CompletedAt: &completedAt}})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"merge"}, &out); err != nil {
+		t.Fatalf("merge = %v", err)
+	}
+	ready := loadTodos(readyFile)
+	if len(ready) != 2 || ready[0].Text != "Pay rent and council tax" || ready[1].Text != "Renew passport" {
+		t.Errorf("ready = %+v, want the rename and the new todo", ready)
+	}
+	if _, err := os.Stat(conflicted); !os.IsNotExist(err) {
+		t.Error("the conflicted copy should be moved out of the way")
+	}
+	if _, err := os.Stat(filepath.Join("backup", conflicted)); err != nil {
+		t.Error("the conflicted copy should be kept in backup/")
+	}
+	if !strings.Contains(out.String(), "Merged 1 conflicted copy into todo_ready.txt") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	out.Reset()
+	RunCommand([]string{"merge"}, &out)
+	if out.String() != "No conflicted copies found\n" {
+		t.Errorf("output = %q, want nothing left to merge", out.String())
+	}
+
+	// A later copy with the same name doesn't replace the one already kept
+	saveTodos(conflicted, []Todo{{ID: "1", Text: "Pay rent and council tax"}})
+	RunCommand([]string{"merge"}, &out)
+	if kept, _ := filepath.Glob(filepath.Join("backup", "todo_ready (laptop's conflicted copy 2026-03-01)*.txt")); len(kept) != 2 {
+		t.Errorf("backup/ holds %q, want both conflicted copies kept", kept)
+	}
+}
+
+func TestMergeBaseIsOlderThanTheCopy(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// Both machines start from the same backup
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Pay rent"}, {ID: "2", Text: "Book flights"}})
+	createBackups()
+	time.Sleep(10 * time.Millisecond)
+
+	// Then each renames the same todo, and the laptop also renames another
+	conflicted := "todo_ready (laptop's conflicted copy 2026-03-01).txt"
+	saveTodos(conflicted, []Todo{{ID: "1", Text: "Pay rent and council tax"}, {ID: "2", Text: "Book flights to Rome"}})
+	time.Sleep(10 * time.Millisecond)
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Pay rent today"}, {ID: "2", Text: "Book flights"}})
+
+	// Starting the app backs up this machine's side, which mustn't be taken as the base
+	m := InitialModel()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if len(m.ready) != 2 || m.ready[0].Text != "Pay rent today" || m.ready[1].Text != "Book flights to Rome" {
+		t.Errorf("ready = %+v, want this machine's rename kept and the laptop's other rename taken", m.ready)
+	}
+	if !strings.Contains(m.fileWarning, `"Pay rent today" has a different text in each copy`) {
+		t.Errorf("fileWarning = %q, want the conflicting rename reported", m.fileWarning)
+	}
+
+	// Without a backup older than the copy, every difference is a conflict
+	os.RemoveAll(backupDir)
+	saveTodos(conflicted, []Todo{{ID: "1", Text: "Pay rent monthly"}})
+	if base := mergeBase(readyFile, conflicted, m.ready, loadTodos(conflicted)); base != nil {
+		t.Errorf("mergeBase() = %+v, want no base", base)
+	}
+}
+
+func TestDropDuplicatesAcrossColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	completedAt := time.Now()
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Done elsewhere"}, {ID: "2", Text: "Still to do"}, {ID: "3", Text: "Renamed here"}})
+	saveTodos(completedFile, []Todo{
+		{ID: "1", Text: "Done elsewhere", CompletedAt: &completedAt},
+		{ID: "3", Text: "Renamed and done elsewhere", CompletedAt: &completedAt},
+	})
+
+	report, err := dropDuplicatesAcrossColumns()
+	if err != nil || len(report) != 2 || !strings.Contains(report[0], `removed "Done elsewhere" from Ready`) ||
+		!strings.Contains(report[1], `conflict: "Renamed here" is in both Ready and Completed`) {
+		t.Errorf("dropDuplicatesAcrossColumns() = %q, %v", report, err)
+	}
+	if ready := loadTodos(readyFile); len(ready) != 2 || ready[0].ID != "2" || ready[1].ID != "3" {
+		t.Errorf("ready = %+v, want only the unchanged completed todo removed", ready)
+	}
+
+	// The files were backed up before the todo was removed
+	snapshots, _ := listSnapshots()
+	if len(snapshots) != 1 || len(readSnapshotTodos(snapshots[0], readyFile)) != 3 {
+		t.Errorf("snapshots = %+v, want the ready file backed up as it was", snapshots)
+	}
+}
+
+func TestRunCommandMergeKeepsSameLegacyLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// The same plain-text todo in two columns is two todos, not one moved
+	os.WriteFile(backlogFile, []byte("call mom\n"), 0644)
+	os.WriteFile(completedFile, []byte("call mom\n"), 0644)
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Pay rent"}})
+	saveTodos("todo_ready (laptop's conflicted copy 2026-03-01).txt", []Todo{{ID: "1", Text: "Pay rent"}})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"merge"}, &out); err != nil {
+		t.Fatalf("merge = %v", err)
+	}
+	if backlog, completed := loadTodos(backlogFile), loadTodos(completedFile); len(backlog) != 1 || len(completed) != 1 {
+		t.Errorf("backlog = %+v, completed = %+v, want both todos kept", backlog, completed)
+	}
+	if strings.Contains(out.String(), "removed") {
+		t.Errorf("output = %q, want nothing removed", out.String())
+	}
+}
+
+func TestStartupMergePrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(backlogFile, []Todo{{ID: "1", Text: "Mine"}})
+	conflicted := "todo_backlog (conflicted copy).txt"
+	saveTodos(conflicted, []Todo{{ID: "2", Text: "Theirs"}})
+
+	m := InitialModel()
+	if !m.confirmingMerge {
+		t.Fatal("InitialModel should offer to merge the conflicted copy")
+	}
+	if view := m.View(); !strings.Contains(view, conflicted) {
+		t.Errorf("View should list the conflicted copy:\n%s", view)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if m.confirmingMerge || len(m.backlog) != 2 || !strings.HasPrefix(m.message, "Merged 1 conflicted copy") {
+		t.Errorf("backlog = %+v, message = %q, want both todos after merging", m.backlog, m.message)
+	}
+	if m.changedOnDisk(backlogFile) {
+		t.Error("the merged file should be tracked")
+	}
+}
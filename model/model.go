@@ -1,31 +1,76 @@
 package model
 
 import (
+	"errors"
 	"log"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // InitialModel creates and returns the initial model state
 func InitialModel() Model {
-	// Create backups of all todo files at startup
-	err := createBackups()
-	if err != nil {
-		log.Fatal("Error creating backups: ", err)
-	}
-
 	m := Model{
-		backlog:     loadTodos(backlogFile),
-		ready:       loadTodos(readyFile),
-		completed:   loadTodos(completedFile),
 		cursor:      0,
 		currentView: viewReady,
 	}
+
+	// Only one copy of the app edits the files at a time; others open them read-only
+	var locked *LockedError
+	if err := acquireLock(); errors.As(err, &locked) {
+		m.readOnly = true
+		m.lockOwner = locked.Owner
+	} else if err != nil {
+		log.Fatal("Error locking todo files: ", err)
+	} else {
+		m.holdsLock = true
+		m.lockRefreshed = time.Now()
+
+		// Create backups of all todo files at startup, leaving backup/ alone while
+		// another copy of the app holds the lock
+		if err := createBackups(); err != nil {
+			log.Fatal("Error creating backups: ", err)
+		}
+	}
+
+	// Load each column once, persisting IDs assigned to todos from older files unless
+	// read-only, so the lists shown and the state tracked come from the same read
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		var todos []Todo
+		var err error
+		if m.readOnly {
+			todos, _, err = readTodos(filename)
+		} else {
+			todos, err = loadTodosWithIDs(filename)
+		}
+		if err != nil {
+			log.Fatalf("Error loading %s: %v", filename, err)
+		}
+		if list := m.listFor(v); list != nil {
+			*list = todos
+		} else {
+			m.completed = todos
+		}
+		m.trackFile(filename, todos)
+	}
 	m.updateDisplayedCompleted()
+
+	// Offer to merge copies a sync tool kept when the files changed in two places
+	if !m.readOnly {
+		m.syncConflicts = findSyncConflicts()
+		m.confirmingMerge = len(m.syncConflicts) > 0
+	}
+
+	// A timer left running when the app last quit keeps ticking
+	_, m.timerTicking = m.runningTimer()
 	return m
 }
 
 // Init initializes the model and returns the initial command
 func (m Model) Init() tea.Cmd {
-	return tea.ClearScreen
+	if m.timerTicking {
+		return tea.Batch(tea.ClearScreen, watchFiles(), timerTick())
+	}
+	return tea.Batch(tea.ClearScreen, watchFiles())
 }
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// priorities lists priority levels from most to least urgent. Todos without a
+// priority sort after all of them.
+var priorities = []string{"P0", "P1", "P2", "P3"}
+
+// priorityRank returns the position of p in priorities, or len(priorities) if unset
+func priorityRank(p string) int {
+	for i, level := range priorities {
+		if level == p {
+			return i
+		}
+	}
+	return len(priorities)
+}
+
+// parsePriority accepts "P1", "p1" or "1", returning the canonical level
+func parsePriority(input string) (string, error) {
+	level := strings.ToUpper(strings.TrimSpace(input))
+	if !strings.HasPrefix(level, "P") {
+		level = "P" + level
+	}
+	if priorityRank(level) == len(priorities) {
+		return "", fmt.Errorf("invalid priority %q (use P0 to P3)", input)
+	}
+	return level, nil
+}
+
+// raisePriority returns the next more urgent level. Unset todos become the least
+// urgent level, and P0 stays P0.
+func raisePriority(p string) string {
+	rank := priorityRank(p)
+	if rank == 0 {
+		return p
+	}
+	return priorities[rank-1]
+}
+
+// lowerPriority returns the next less urgent level, clearing the priority below the
+// least urgent one
+func lowerPriority(p string) string {
+	rank := priorityRank(p)
+	if rank >= len(priorities)-1 {
+		return ""
+	}
+	return priorities[rank+1]
+}
+
+// priorityBefore orders todos by priority, with todos without one last
+func priorityBefore(a, b Todo) bool {
+	return priorityRank(a.Priority) < priorityRank(b.Priority)
+}
+
+// renderPriority renders a coloured badge for the todo's priority, or "" if unset
+func renderPriority(p string) string {
+	rank := priorityRank(p)
+	if rank >= len(priorityStyles) {
+		return ""
+	}
+	return priorityStyles[rank].Render(p)
+}
+
+// setSelectedPriority changes the priority of the todo under the cursor with change
+// and saves its list
+func (m *Model) setSelectedPriority(change func(string) string) tea.Cmd {
+	idx := m.selectedIndex()
+	if idx < 0 {
+		return nil
+	}
+
+	list := *m.listFor(m.currentView)
+	filename := columnOf(m.currentView).file
+
+	newPriority := change(list[idx].Priority)
+	if newPriority == list[idx].Priority {
+		if newPriority == "" {
+			m.message = "No priority set"
+		} else {
+			m.message = "Already " + newPriority
+		}
+		return nil
+	}
+
+	m.pushUndo()
+	list[idx].Priority = newPriority
+	if cmd := m.save(filename, list); cmd != nil {
+		return cmd
+	}
+
+	if newPriority == "" {
+		m.message = "Priority removed"
+	} else {
+		m.message = "Priority set to " + newPriority
+	}
+
+	// Keep the cursor on the todo when the list is sorted by priority
+	for i, visibleIdx := range m.visibleIndices() {
+		if visibleIdx == idx {
+			m.cursor = i
+			break
+		}
+	}
+	return nil
+}
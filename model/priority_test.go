@@ -0,0 +1,186 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"P0", "P0", false},
+		{"p2", "P2", false},
+		{" 3 ", "P3", false},
+		{"P4", "", true},
+		{"high", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePriority(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePriority(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.expected {
+			t.Errorf("parsePriority(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRaiseLowerPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(string) string
+		from     string
+		expected string
+	}{
+		{"raise unset", raisePriority, "", "P3"},
+		{"raise P2", raisePriority, "P2", "P1"},
+		{"raise P0 stays", raisePriority, "P0", "P0"},
+		{"lower P0", lowerPriority, "P0", "P1"},
+		{"lower P3 clears", lowerPriority, "P3", ""},
+		{"lower unset stays", lowerPriority, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change(tt.from); got != tt.expected {
+				t.Errorf("%s(%q) = %q, want %q", tt.name, tt.from, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderPriority(t *testing.T) {
+	if renderPriority("") != "" {
+		t.Error("renderPriority() without a priority should be empty")
+	}
+	if !strings.Contains(renderPriority("P1"), "P1") {
+		t.Error("renderPriority() should contain the level")
+	}
+}
+
+func TestUpdateChangePriority(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog:     []Todo{{ID: "1", Text: "Fix outage", CreatedAt: time.Now()}},
+	}
+
+	for i := 0; i < 5; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+		m = updated.(Model)
+	}
+	if m.backlog[0].Priority != "P0" || m.message != "Already P0" {
+		t.Errorf("Priority/message = %q/%q, want P0 and 'Already P0'", m.backlog[0].Priority, m.message)
+	}
+	if saved := loadTodos(backlogFile); saved[0].Priority != "P0" {
+		t.Errorf("saved priority = %q, want P0", saved[0].Priority)
+	}
+	if !strings.Contains(m.View(), "P0") {
+		t.Error("View should show the priority badge")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}})
+	m = updated.(Model)
+	if m.backlog[0].Priority != "P1" {
+		t.Errorf("Priority after '-' = %q, want P1", m.backlog[0].Priority)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if m.backlog[0].Priority != "P0" {
+		t.Errorf("Priority after undo = %q, want P0", m.backlog[0].Priority)
+	}
+}
+
+func TestUpdateSortByPriority(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "unset", CreatedAt: now},
+			{ID: "2", Text: "low", Priority: "P3", CreatedAt: now},
+			{ID: "3", Text: "urgent", Priority: "P0", CreatedAt: now},
+			{ID: "4", Text: "also low", Priority: "P3", CreatedAt: now},
+		},
+	}
+	saveTodos(readyFile, m.ready)
+
+	// S cycles to due date order, then priority order
+	for i := 0; i < 2; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+		m = updated.(Model)
+	}
+	if m.sortMode != sortPriority {
+		t.Fatalf("sortMode = %v, want priority", m.sortMode)
+	}
+
+	// Ties keep manual order
+	var ids []string
+	for _, todo := range m.getCurrentList() {
+		ids = append(ids, todo.ID)
+	}
+	if strings.Join(ids, ",") != "3,2,4,1" {
+		t.Errorf("priority order = %v, want 3,2,4,1", ids)
+	}
+	if !strings.Contains(m.View(), "Sorted by priority") {
+		t.Error("View should say the list is sorted by priority")
+	}
+
+	// Raising a todo keeps the cursor on it as it moves up
+	m.cursor = 3
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	m = updated.(Model)
+	if m.getCurrentList()[m.cursor].ID != "1" {
+		t.Errorf("cursor on %q after raising, want 1", m.getCurrentList()[m.cursor].ID)
+	}
+
+	// The saved manual order is unchanged
+	saved := loadTodos(readyFile)
+	if saved[0].ID != "1" || saved[2].ID != "3" {
+		t.Errorf("saved order = %v, want manual order", saved)
+	}
+}
+
+func TestRunCommandAddPriority(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"add", "page oncall", "--priority", "p0"}, &out); err != nil {
+		t.Fatalf("RunCommand(add --priority) error = %v", err)
+	}
+	if ready := loadTodos(readyFile); len(ready) != 1 || ready[0].Priority != "P0" {
+		t.Fatalf("ready = %v, want one P0 todo", ready)
+	}
+
+	out.Reset()
+	RunCommand([]string{"list"}, &out)
+	if !strings.Contains(out.String(), "[P0] Page oncall") {
+		t.Errorf("list output = %q, want priority prefix", out.String())
+	}
+
+	if err := RunCommand([]string{"add", "x", "--priority", "P9"}, &out); err == nil {
+		t.Error("RunCommand(add --priority P9) should return an error")
+	}
+}
@@ -0,0 +1,180 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceKind is how often a recurring todo repeats
+type recurrenceKind int
+
+const (
+	recurDaily recurrenceKind = iota
+	recurWeekdays
+	recurWeekly
+	recurMonthly
+	recurEveryNDays
+)
+
+// recurrence is a parsed Todo.Recurrence rule
+type recurrence struct {
+	kind     recurrenceKind
+	weekday  time.Weekday // Day of the week for recurWeekly
+	monthDay int          // Day of the month for recurMonthly, clamped to short months
+	interval int          // Number of days for recurEveryNDays
+}
+
+// errRecurrenceHelp lists the accepted recurrence rules
+var errRecurrenceHelp = errors.New("use daily, weekdays, weekly <day>, monthly <date> or every <n> days")
+
+// parseRecurrence parses a rule such as "daily", "weekdays", "weekly fri", "monthly 15"
+// or "every 3 days" ("every 3d" also works). A weekly or monthly rule without a day
+// repeats on the weekday or date of now.
+func parseRecurrence(input string, now time.Time) (recurrence, error) {
+	var fields []string
+	for _, field := range strings.Fields(strings.ToLower(input)) {
+		// Allow "weekly on fri" and "monthly on 15"
+		if field != "on" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return recurrence{}, errRecurrenceHelp
+	}
+
+	switch fields[0] {
+	case "daily":
+		if len(fields) == 1 {
+			return recurrence{kind: recurDaily}, nil
+		}
+	case "weekdays":
+		if len(fields) == 1 {
+			return recurrence{kind: recurWeekdays}, nil
+		}
+	case "weekly":
+		if len(fields) == 1 {
+			return recurrence{kind: recurWeekly, weekday: now.Weekday()}, nil
+		}
+		if weekday, ok := weekdayNames[fields[1]]; ok && len(fields) == 2 {
+			return recurrence{kind: recurWeekly, weekday: weekday}, nil
+		}
+	case "monthly":
+		if len(fields) == 1 {
+			return recurrence{kind: recurMonthly, monthDay: now.Day()}, nil
+		}
+		if day, err := strconv.Atoi(strings.TrimRight(fields[1], "stndrh")); err == nil && day >= 1 && day <= 31 && len(fields) == 2 {
+			return recurrence{kind: recurMonthly, monthDay: day}, nil
+		}
+	case "every":
+		if len(fields) == 2 && strings.HasSuffix(fields[1], "d") {
+			fields = []string{"every", strings.TrimSuffix(fields[1], "d"), "days"}
+		}
+		if len(fields) == 3 && (fields[2] == "days" || fields[2] == "day") {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n == 1 {
+				return recurrence{kind: recurDaily}, nil
+			} else if err == nil && n > 1 {
+				return recurrence{kind: recurEveryNDays, interval: n}, nil
+			}
+		}
+	}
+	return recurrence{}, fmt.Errorf("can't understand recurrence %q (%v)", input, errRecurrenceHelp)
+}
+
+// String formats the rule the way it is stored in Todo.Recurrence
+func (r recurrence) String() string {
+	switch r.kind {
+	case recurWeekdays:
+		return "weekdays"
+	case recurWeekly:
+		return "weekly " + strings.ToLower(r.weekday.String()[:3])
+	case recurMonthly:
+		return fmt.Sprintf("monthly %d", r.monthDay)
+	case recurEveryNDays:
+		return fmt.Sprintf("every %d days", r.interval)
+	default:
+		return "daily"
+	}
+}
+
+// next returns the first date strictly after the day of after on which the rule falls,
+// at midnight local time
+func (r recurrence) next(after time.Time) time.Time {
+	day := truncateToDay(after)
+	switch r.kind {
+	case recurEveryNDays:
+		return day.AddDate(0, 0, r.interval)
+	case recurMonthly:
+		for month := 0; ; month++ {
+			first := time.Date(day.Year(), day.Month()+time.Month(month), 1, 0, 0, 0, 0, day.Location())
+			lastDay := first.AddDate(0, 1, -1).Day()
+			date := first.AddDate(0, 0, min(r.monthDay, lastDay)-1)
+			if date.After(day) {
+				return date
+			}
+		}
+	default:
+		for {
+			day = day.AddDate(0, 0, 1)
+			switch {
+			case r.kind == recurDaily,
+				r.kind == recurWeekdays && day.Weekday() != time.Saturday && day.Weekday() != time.Sunday,
+				r.kind == recurWeekly && day.Weekday() == r.weekday:
+				return day
+			}
+		}
+	}
+}
+
+// first returns the first date from the day of now on which the rule falls
+func (r recurrence) first(now time.Time) time.Time {
+	if r.kind == recurEveryNDays {
+		return truncateToDay(now)
+	}
+	return r.next(truncateToDay(now).AddDate(0, 0, -1))
+}
+
+// nextOccurrence returns a fresh copy of a completed recurring todo, due on the next
+// date its rule falls after both its previous due date and completedAt. It returns
+// false if the todo doesn't recur.
+func nextOccurrence(todo Todo, completedAt time.Time) (Todo, bool) {
+	if todo.Recurrence == "" {
+		return Todo{}, false
+	}
+	rule, err := parseRecurrence(todo.Recurrence, completedAt)
+	if err != nil {
+		return Todo{}, false
+	}
+
+	// Completing early or late both move on to the next date after today
+	after := completedAt
+	if todo.DueAt != nil && todo.DueAt.After(after) {
+		after = *todo.DueAt
+	}
+	due := rule.next(after)
+
+	// The checklist starts again from scratch
+	var checklist []ChecklistItem
+	for _, item := range todo.Checklist {
+		checklist = append(checklist, ChecklistItem{Text: item.Text})
+	}
+
+	return Todo{
+		ID:         newTodoID(),
+		Text:       todo.Text,
+		Tags:       append([]string(nil), todo.Tags...),
+		Checklist:  checklist,
+		Priority:   todo.Priority,
+		Recurrence: todo.Recurrence,
+		CreatedAt:  completedAt,
+		DueAt:      &due,
+	}, true
+}
+
+// belongsInReady reports whether a regenerated recurring todo is due soon enough to go
+// straight to ready; later ones wait at the top of the backlog
+func belongsInReady(todo Todo, now time.Time) bool {
+	return todo.DueAt == nil || daysUntilDue(*todo.DueAt, now) <= dueSoonDays
+}
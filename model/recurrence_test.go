@@ -0,0 +1,231 @@
+package model
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	// Saturday, October 17 2026
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"daily", "daily"},
+		{"Weekdays", "weekdays"},
+		{"weekly fri", "weekly fri"},
+		{"weekly friday", "weekly fri"},
+		{"weekly", "weekly sat"},
+		{"monthly 15", "monthly 15"},
+		{"monthly 1st", "monthly 1"},
+		{"monthly", "monthly 17"},
+		{"every 3 days", "every 3 days"},
+		{"every 10d", "every 10 days"},
+		{"every 1 day", "daily"},
+		{"weekly on mon", "weekly mon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rule, err := parseRecurrence(tt.input, now)
+			if err != nil {
+				t.Fatalf("parseRecurrence(%q) error = %v", tt.input, err)
+			}
+			if rule.String() != tt.expected {
+				t.Errorf("parseRecurrence(%q) = %q, want %q", tt.input, rule.String(), tt.expected)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "yearly", "weekly someday", "monthly 32", "every 0 days", "every few days", "daily please"} {
+		if _, err := parseRecurrence(invalid, now); err == nil {
+			t.Errorf("parseRecurrence(%q) should return an error", invalid)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.Local) }
+	// Friday, October 16 2026 in the afternoon
+	after := time.Date(2026, 10, 16, 17, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		rule     recurrence
+		after    time.Time
+		expected time.Time
+	}{
+		{"daily", recurrence{kind: recurDaily}, after, day(10, 17)},
+		{"weekdays skip weekend", recurrence{kind: recurWeekdays}, after, day(10, 19)},
+		{"weekly later this week", recurrence{kind: recurWeekly, weekday: time.Sunday}, after, day(10, 18)},
+		{"weekly same day is next week", recurrence{kind: recurWeekly, weekday: time.Friday}, after, day(10, 23)},
+		{"monthly later this month", recurrence{kind: recurMonthly, monthDay: 20}, after, day(10, 20)},
+		{"monthly next month", recurrence{kind: recurMonthly, monthDay: 3}, after, day(11, 3)},
+		{"monthly short month clamps", recurrence{kind: recurMonthly, monthDay: 31}, day(10, 31), day(11, 30)},
+		{"every n days", recurrence{kind: recurEveryNDays, interval: 3}, after, day(10, 19)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.next(tt.after); !got.Equal(tt.expected) {
+				t.Errorf("next(%v) = %v, want %v", tt.after, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	completedAt := time.Date(2026, 10, 16, 17, 0, 0, 0, time.Local)
+	due := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	todo := Todo{
+		ID:           "old",
+		Text:         "Weekly report",
+		Tags:         []string{"reports"},
+		Priority:     "P2",
+		Recurrence:   "weekly wed",
+		Updates:      textUpdates("sent to team"),
+		CompleteNote: "done",
+		DueAt:        &due,
+		CompletedAt:  &completedAt,
+	}
+
+	next, ok := nextOccurrence(todo, completedAt)
+	if !ok {
+		t.Fatal("nextOccurrence() should regenerate a recurring todo")
+	}
+	// Completed late, so the next one is after today rather than a week after the old due date
+	if expected := time.Date(2026, 10, 21, 0, 0, 0, 0, time.Local); !next.DueAt.Equal(expected) {
+		t.Errorf("next DueAt = %v, want %v", next.DueAt, expected)
+	}
+	if next.ID == "old" || next.ID == "" {
+		t.Errorf("next ID = %q, want a new ID", next.ID)
+	}
+	if next.Text != todo.Text || next.Priority != "P2" || next.Recurrence != "weekly wed" || len(next.Tags) != 1 {
+		t.Errorf("next = %+v, want text, priority, recurrence and tags copied", next)
+	}
+	if next.CompletedAt != nil || len(next.Updates) != 0 || next.CompleteNote != "" {
+		t.Errorf("next = %+v, want a fresh todo without history", next)
+	}
+
+	if _, ok := nextOccurrence(Todo{Text: "one-off"}, completedAt); ok {
+		t.Error("nextOccurrence() should not regenerate a one-off todo")
+	}
+}
+
+func TestUpdateCompleteRecurringTodo(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	today := truncateToDay(time.Now())
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "daily", Text: "Check alerts", Recurrence: "daily", DueAt: &today, CreatedAt: today},
+			{ID: "monthly", Text: "Rotate certs", Recurrence: "every 30 days", DueAt: &today, CreatedAt: today},
+		},
+	}
+
+	// A daily todo is due tomorrow, so it goes straight back to ready
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.completed) != 1 || m.completed[0].ID != "daily" {
+		t.Fatalf("completed = %v, want the daily todo", m.completed)
+	}
+	if len(m.ready) != 2 || m.ready[1].Text != "Check alerts" || m.ready[1].ID == "daily" {
+		t.Fatalf("ready = %v, want a new daily todo at the end", m.ready)
+	}
+	if !strings.Contains(m.message, "Next one due tomorrow") {
+		t.Errorf("message = %q, want next due date", m.message)
+	}
+
+	// A todo due in 30 days waits at the top of the backlog
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.backlog) != 1 || m.backlog[0].Text != "Rotate certs" {
+		t.Fatalf("backlog = %v, want the next certificate rotation", m.backlog)
+	}
+	if saved := loadTodos(backlogFile); len(saved) != 1 {
+		t.Errorf("saved backlog len = %d, want 1", len(saved))
+	}
+	if saved := loadTodos(completedFile); len(saved) != 2 {
+		t.Errorf("saved completed len = %d, want 2", len(saved))
+	}
+}
+
+func TestUpdateSetRecurrence(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog:     []Todo{{ID: "1", Text: "Water plants", CreatedAt: time.Now()}},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	m = updated.(Model)
+	if !m.editingRecurrence {
+		t.Fatal("'R' should open the recurrence prompt")
+	}
+
+	m.newRecurrence = "every other day"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.editingRecurrence || !strings.Contains(m.message, "can't understand") {
+		t.Fatalf("editingRecurrence/message = %v/%q, want prompt kept open with an error", m.editingRecurrence, m.message)
+	}
+
+	m.newRecurrence = "Every 2d"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.backlog[0].Recurrence != "every 2 days" {
+		t.Errorf("Recurrence = %q, want normalised rule", m.backlog[0].Recurrence)
+	}
+	// Without a due date the first occurrence is today
+	if m.backlog[0].DueAt == nil || !m.backlog[0].DueAt.Equal(truncateToDay(time.Now())) {
+		t.Errorf("DueAt = %v, want today", m.backlog[0].DueAt)
+	}
+	if !strings.Contains(m.View(), "↻ every 2 days") {
+		t.Error("View should show the recurrence")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	m = updated.(Model)
+	m.newRecurrence = ""
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.backlog[0].Recurrence != "" || m.message != "Recurrence removed" {
+		t.Errorf("Recurrence/message = %q/%q, want removed", m.backlog[0].Recurrence, m.message)
+	}
+}
+
+func TestRunCommandDoneRecurring(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	today := truncateToDay(time.Now())
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Standup notes", Recurrence: "daily", DueAt: &today, CreatedAt: today}})
+
+	var out bytes.Buffer
+	if err := RunCommand([]string{"done", "1"}, &out); err != nil {
+		t.Fatalf("RunCommand(done) error = %v", err)
+	}
+	if ready := loadTodos(readyFile); len(ready) != 1 || ready[0].ID == "1" {
+		t.Errorf("ready = %v, want the next occurrence", ready)
+	}
+	if !strings.Contains(out.String(), "Next one due tomorrow") {
+		t.Errorf("output = %q, want next due date", out.String())
+	}
+}
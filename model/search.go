@@ -0,0 +1,230 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// searchMatch is a todo matching the current search query
+type searchMatch struct {
+	view    view   // List the todo lives in (ignored for archive matches)
+	archive string // Backup file the todo was found in, empty for the live lists
+	todo    Todo
+}
+
+// archivedTodo is a todo loaded from a todo_completed_backup_*.txt file
+type archivedTodo struct {
+	file string
+	todo Todo
+}
+
+// startSearch enters search mode, remembering where the cursor was so Esc can return to it
+func (m *Model) startSearch() {
+	m.searching = true
+	m.searchActive = false
+	m.searchQuery = ""
+	m.searchMatches = nil
+	m.searchIndex = 0
+	m.searchOrigin = searchOrigin{view: m.currentView, cursor: m.cursor}
+	m.textInputCursor = 0
+	m.message = ""
+
+	// Archives don't change while the app is running, so load them once per search
+	m.searchArchives = nil
+	backupFiles, err := findBackupFiles()
+	if err != nil {
+		return
+	}
+	for _, file := range backupFiles {
+		for _, todo := range loadTodos(file) {
+			m.searchArchives = append(m.searchArchives, archivedTodo{file: file, todo: todo})
+		}
+	}
+}
+
+// clearSearch leaves search mode and removes highlighting
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.searchActive = false
+	m.searchQuery = ""
+	m.searchMatches = nil
+	m.searchIndex = 0
+	m.searchArchives = nil
+}
+
+// todoMatchesQuery reports whether todo contains query (case-insensitive). Updates and
+// the complete note are only searched when includeDetails is set.
+func todoMatchesQuery(todo Todo, query string, includeDetails bool) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(todo.Text), query) {
+		return true
+	}
+	if !includeDetails {
+		return false
+	}
+	if strings.Contains(strings.ToLower(todo.CompleteNote), query) {
+		return true
+	}
+	for _, update := range todo.Updates {
+		if strings.Contains(strings.ToLower(update.Text), query) {
+			return true
+		}
+	}
+	for _, item := range todo.Checklist {
+		if strings.Contains(strings.ToLower(item.Text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateSearchMatches recomputes matches for the current query across every column in
+// order (completed most recent first) and then the backup archives
+func (m *Model) updateSearchMatches() {
+	m.searchMatches = nil
+	m.searchIndex = 0
+	if strings.TrimSpace(m.searchQuery) == "" {
+		return
+	}
+
+	for _, v := range columnOrder() {
+		todos := sortByCompletedDesc(m.completed)
+		if list := m.listFor(v); list != nil {
+			todos = *list
+		}
+		for _, todo := range todos {
+			if todoMatchesQuery(todo, m.searchQuery, m.searchAll) {
+				m.searchMatches = append(m.searchMatches, searchMatch{view: v, todo: todo})
+			}
+		}
+	}
+	for _, archived := range m.searchArchives {
+		if todoMatchesQuery(archived.todo, m.searchQuery, m.searchAll) {
+			m.searchMatches = append(m.searchMatches, searchMatch{archive: archived.file, todo: archived.todo})
+		}
+	}
+}
+
+// jumpToFirstMatch selects the first match at or after the position search started from
+func (m *Model) jumpToFirstMatch() {
+	if len(m.searchMatches) == 0 {
+		m.currentView = m.searchOrigin.view
+		m.cursor = m.searchOrigin.cursor
+		return
+	}
+
+	// Prefer a match in the list the search started in, at or below the original cursor
+	m.currentView = m.searchOrigin.view
+	m.updateDisplayedCompleted()
+	list := m.getCurrentList()
+	for i, match := range m.searchMatches {
+		if match.archive != "" || match.view != m.searchOrigin.view {
+			continue
+		}
+		if idx := findTodoIndex(list, match.todo); idx >= m.searchOrigin.cursor {
+			m.searchIndex = i
+			m.jumpToMatch()
+			return
+		}
+	}
+	m.searchIndex = 0
+	m.jumpToMatch()
+}
+
+// jumpToMatch moves the view and cursor to the current match
+func (m *Model) jumpToMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	match := m.searchMatches[m.searchIndex]
+	position := fmt.Sprintf("Match %d of %d", m.searchIndex+1, len(m.searchMatches))
+
+	if match.archive != "" {
+		m.message = fmt.Sprintf("%s in %s: %s", position, match.archive, match.todo.Text)
+		return
+	}
+
+	m.currentView = match.view
+	m.updateDisplayedCompleted()
+	if match.view == viewCompleted {
+		// The match may be on another page of the completed history
+		if i := findTodoIndex(m.completedHistory(), match.todo); i >= 0 {
+			if delta := i/cfg.completedLimit - m.completedPage; delta != 0 {
+				m.pageCompleted(delta)
+			}
+		}
+	}
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+	m.showingPrettify = false
+	if idx := findTodoIndex(m.getCurrentList(), match.todo); idx >= 0 {
+		m.cursor = idx
+		m.message = position
+	} else {
+		m.cursor = 0
+		m.message = fmt.Sprintf("%s is not shown in %s: %s", position, viewName(match.view), match.todo.Text)
+	}
+}
+
+// nextMatch moves to the next (delta 1) or previous (delta -1) match, wrapping around
+func (m *Model) nextMatch(delta int) {
+	// Lists may have changed since the search was confirmed
+	current := m.searchIndex
+	m.updateSearchMatches()
+	if current < len(m.searchMatches) {
+		m.searchIndex = current
+	}
+	if len(m.searchMatches) == 0 {
+		m.message = fmt.Sprintf("No matches for %q", m.searchQuery)
+		return
+	}
+	m.searchIndex = (m.searchIndex + delta + len(m.searchMatches)) % len(m.searchMatches)
+	m.jumpToMatch()
+}
+
+// isSearchHighlighted reports whether todo should be highlighted as a search match
+func (m *Model) isSearchHighlighted(todo Todo) bool {
+	if !m.searching && !m.searchActive {
+		return false
+	}
+	return todoMatchesQuery(todo, m.searchQuery, m.searchAll)
+}
+
+// highlightQuery renders text with case-insensitive occurrences of query in matchStyle
+// and the rest in todoTextStyle
+func highlightQuery(text, query string) string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return todoTextStyle.Render(text)
+	}
+
+	lowerText := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+	// Lowercasing can change byte lengths for some scripts; only highlight when offsets line up
+	if len(lowerText) != len(text) {
+		return searchMatchStyle.Render(text)
+	}
+
+	var sb strings.Builder
+	for {
+		idx := strings.Index(lowerText, lowerQuery)
+		if idx < 0 {
+			sb.WriteString(todoTextStyle.Render(text))
+			break
+		}
+		if idx > 0 {
+			sb.WriteString(todoTextStyle.Render(text[:idx]))
+		}
+		sb.WriteString(searchMatchStyle.Render(text[idx : idx+len(lowerQuery)]))
+		text = text[idx+len(lowerQuery):]
+		lowerText = lowerText[idx+len(lowerQuery):]
+		if text == "" {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,259 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTodoMatchesQuery(t *testing.T) {
+	todo := Todo{
+		Text:         "Deploy Infra changes",
+		CompleteNote: "rolled out to staging",
+		Updates:      textUpdates("waiting on review"),
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		includeDetails bool
+		expected       bool
+	}{
+		{"text match", "infra", false, true},
+		{"case insensitive", "DEPLOY", false, true},
+		{"no match", "database", true, false},
+		{"empty query", "  ", true, false},
+		{"update without details", "review", false, false},
+		{"update with details", "review", true, true},
+		{"complete note with details", "staging", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := todoMatchesQuery(todo, tt.query, tt.includeDetails); got != tt.expected {
+				t.Errorf("todoMatchesQuery(%q, %v) = %v, want %v", tt.query, tt.includeDetails, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlightQuery(t *testing.T) {
+	result := highlightQuery("Fix the login bug", "LOGIN")
+	if !strings.Contains(result, "login") {
+		t.Errorf("highlightQuery() = %q, want to preserve original text", result)
+	}
+	if !strings.Contains(result, "Fix the ") || !strings.Contains(result, " bug") {
+		t.Errorf("highlightQuery() = %q, want surrounding text", result)
+	}
+
+	if highlightQuery("plain", "") != todoTextStyle.Render("plain") {
+		t.Error("highlightQuery() with empty query should render plain text")
+	}
+}
+
+func TestSearchAcrossLists(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	saveTodos("todo_completed_backup_2024-01-01_1.txt", []Todo{
+		{ID: "arch", Text: "Old report", CreatedAt: now, CompletedAt: &now},
+	})
+
+	m := Model{
+		currentView: viewReady,
+		backlog:     []Todo{{ID: "b1", Text: "Write report draft", CreatedAt: now}},
+		ready: []Todo{
+			{ID: "r1", Text: "Unrelated", CreatedAt: now},
+			{ID: "r2", Text: "Send report", CreatedAt: now},
+		},
+		completed: []Todo{{ID: "c1", Text: "Report kickoff", CreatedAt: now, CompletedAt: &now}},
+	}
+	m.updateDisplayedCompleted()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	if !m.searching {
+		t.Fatal("'/' should enter search mode")
+	}
+
+	for _, r := range "report" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+
+	if len(m.searchMatches) != 4 {
+		t.Fatalf("searchMatches len = %d, want 4 (backlog, ready, completed, archive)", len(m.searchMatches))
+	}
+	// Incremental search jumps to the first match in the current list
+	if m.currentView != viewReady || m.cursor != 1 {
+		t.Errorf("view/cursor while typing = %v/%d, want ready/1", m.currentView, m.cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.searching || !m.searchActive {
+		t.Fatal("Enter should confirm the search")
+	}
+
+	// n moves to completed, then to the archive, then wraps to backlog
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if m.currentView != viewCompleted || m.cursor != 0 {
+		t.Errorf("view/cursor after n = %v/%d, want completed/0", m.currentView, m.cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if !strings.Contains(m.message, "todo_completed_backup_2024-01-01_1.txt") {
+		t.Errorf("message for archive match = %q, want archive filename", m.message)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if m.currentView != viewBacklog || m.cursor != 0 {
+		t.Errorf("view/cursor after wrap = %v/%d, want backlog/0", m.currentView, m.cursor)
+	}
+
+	// N goes back
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	m = updated.(Model)
+	if !strings.Contains(m.message, "Match 4 of 4") {
+		t.Errorf("message after N = %q, want 'Match 4 of 4'", m.message)
+	}
+
+	// Matches are highlighted while the search is active
+	view := m.View()
+	if !strings.Contains(view, "report") {
+		t.Error("View should contain matching todo")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.searchActive || m.searchQuery != "" {
+		t.Error("Esc should clear the search")
+	}
+
+	// n is rename again once the search is cleared
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if !m.renamingTodo {
+		t.Error("'n' should rename after the search is cleared")
+	}
+}
+
+func TestSearchOlderCompletedPage(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// 15 completed todos, the oldest on the second page
+	now := time.Now()
+	var completed []Todo
+	for days := range 15 {
+		done := now.AddDate(0, 0, -days)
+		completed = append(completed, Todo{ID: fmt.Sprintf("c%d", days), Text: fmt.Sprintf("Task %d", days), CompletedAt: &done})
+	}
+	completed[14].Text = "Old report"
+
+	m := Model{currentView: viewReady, completed: completed}
+	m.updateDisplayedCompleted()
+	for _, key := range []string{"/", "r", "e", "p", "o", "r", "t"} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.currentView != viewCompleted || m.completedPage != 1 {
+		t.Fatalf("view/page = %v/%d, want the second completed page", m.currentView, m.completedPage)
+	}
+	if m.displayedCompleted[m.cursor].Text != "Old report" || !strings.HasPrefix(m.message, "Match 1 of 1") {
+		t.Errorf("cursor on %q, message = %q, want the match selected", m.displayedCompleted[m.cursor].Text, m.message)
+	}
+}
+
+func TestSearchIncludeDetails(t *testing.T) {
+	now := time.Now()
+	m := Model{
+		currentView: viewBacklog,
+		backlog: []Todo{
+			{ID: "1", Text: "Plain", Updates: textUpdates("blocked on vendor"), CreatedAt: now},
+		},
+	}
+	m.startSearch()
+	m.searchQuery = "vendor"
+	m.updateSearchMatches()
+	if len(m.searchMatches) != 0 {
+		t.Errorf("searchMatches len = %d, want 0 when only searching text", len(m.searchMatches))
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if !m.searchAll {
+		t.Fatal("Tab should enable searching updates and notes")
+	}
+	if len(m.searchMatches) != 1 {
+		t.Errorf("searchMatches len = %d, want 1 with details", len(m.searchMatches))
+	}
+	if m.searchQuery != "vendor" {
+		t.Errorf("searchQuery = %q, Tab should not be inserted", m.searchQuery)
+	}
+	if !strings.Contains(m.View(), "match in details") {
+		t.Error("View should flag todos that only match in their details")
+	}
+}
+
+func TestSearchCancelRestoresCursor(t *testing.T) {
+	now := time.Now()
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "alpha", CreatedAt: now},
+			{ID: "2", Text: "beta", CreatedAt: now},
+			{ID: "3", Text: "gamma", CreatedAt: now},
+		},
+		cursor: 2,
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a', 'l'}})
+	m = updated.(Model)
+	if m.cursor != 0 {
+		t.Errorf("cursor while searching = %d, want 0 (wrapped to first match)", m.cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.cursor != 2 || m.searching {
+		t.Errorf("cursor after cancel = %d (searching=%v), want 2 and search closed", m.cursor, m.searching)
+	}
+	if m.message != "Search cancelled" {
+		t.Errorf("message = %q, want 'Search cancelled'", m.message)
+	}
+}
+
+func TestSearchNoMatches(t *testing.T) {
+	m := Model{currentView: viewReady, ready: []Todo{{ID: "1", Text: "alpha", CreatedAt: time.Now()}}}
+	m.startSearch()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z', 'z'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.searchActive {
+		t.Error("search should not stay active without matches")
+	}
+	if !strings.Contains(m.message, "No matches") {
+		t.Errorf("message = %q, want 'No matches'", m.message)
+	}
+}
@@ -0,0 +1,259 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	statsDays   = 14 // Days in the daily chart
+	statsWeeks  = 8  // Weeks in the weekly chart
+	statsMonths = 6  // Months in the monthly chart
+	statsTrend  = 30 // Days in the sparkline
+)
+
+// sparkLevels are the bar heights of a sparkline, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// periodCount is how many todos were completed in a day, week or month
+type periodCount struct {
+	label string
+	count int
+}
+
+// completionStats summarises completed todos for the stats view
+type completionStats struct {
+	total         int
+	today         int
+	thisWeek      int
+	thisMonth     int
+	days          []periodCount // Last statsDays days, oldest first
+	weeks         []periodCount // Last statsWeeks weeks, oldest first
+	months        []periodCount // Last statsMonths months, oldest first
+	trend         []int         // Completions on each of the last statsTrend days, oldest first
+	weekdays      [7]int        // Completions on each weekday, indexed by time.Weekday
+	averageLead   time.Duration // Mean time from creation to completion
+	currentStreak int           // Days in a row up to today (or yesterday) with a completion
+	longestStreak int           // Most days in a row with a completion
+}
+
+// computeStats summarises todos completed up to now. Todos without a completion time
+// are skipped, as are those without a creation time when averaging lead time.
+func computeStats(todos []Todo, now time.Time) completionStats {
+	var s completionStats
+	perDay := map[time.Time]int{}
+	today := truncateToDay(now)
+	weekStart := getWeekStart(now)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var leadTotal time.Duration
+	leads := 0
+	for _, todo := range todos {
+		if todo.CompletedAt == nil {
+			continue
+		}
+		completed := todo.CompletedAt.In(now.Location())
+		day := truncateToDay(completed)
+		perDay[day]++
+		s.total++
+		s.weekdays[completed.Weekday()]++
+		if day.Equal(today) {
+			s.today++
+		}
+		if !day.Before(weekStart) {
+			s.thisWeek++
+		}
+		if !day.Before(monthStart) {
+			s.thisMonth++
+		}
+		if !todo.CreatedAt.IsZero() && completed.After(todo.CreatedAt) {
+			leadTotal += completed.Sub(todo.CreatedAt)
+			leads++
+		}
+	}
+	if leads > 0 {
+		s.averageLead = leadTotal / time.Duration(leads)
+	}
+
+	// countBetween counts completions on days from start up to (not including) end
+	countBetween := func(start, end time.Time) int {
+		count := 0
+		for day, n := range perDay {
+			if !day.Before(start) && day.Before(end) {
+				count += n
+			}
+		}
+		return count
+	}
+
+	for i := statsDays - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		s.days = append(s.days, periodCount{day.Format("Mon Jan 2"), perDay[day]})
+	}
+	for i := statsWeeks - 1; i >= 0; i-- {
+		start := weekStart.AddDate(0, 0, -7*i)
+		s.weeks = append(s.weeks, periodCount{"Wk of " + start.Format("Jan 2"), countBetween(start, start.AddDate(0, 0, 7))})
+	}
+	for i := statsMonths - 1; i >= 0; i-- {
+		start := monthStart.AddDate(0, -i, 0)
+		s.months = append(s.months, periodCount{start.Format("Jan 2006"), countBetween(start, start.AddDate(0, 1, 0))})
+	}
+	for i := statsTrend - 1; i >= 0; i-- {
+		s.trend = append(s.trend, perDay[today.AddDate(0, 0, -i)])
+	}
+
+	s.currentStreak, s.longestStreak = streaks(perDay, today)
+	return s
+}
+
+// streaks returns the current run of consecutive days with completions, ending today
+// or yesterday so a streak isn't lost before today's first completion, and the longest
+// run ever
+func streaks(perDay map[time.Time]int, today time.Time) (current, longest int) {
+	day := today
+	if perDay[day] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for perDay[day] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	days := make([]time.Time, 0, len(perDay))
+	for day := range perDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	run := 0
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return current, longest
+}
+
+// sparkline renders counts as a row of bars scaled to the largest count
+func sparkline(counts []int) string {
+	highest := 0
+	for _, n := range counts {
+		if n > highest {
+			highest = n
+		}
+	}
+	line := make([]rune, len(counts))
+	for i, n := range counts {
+		level := 0
+		if highest > 0 {
+			level = n * (len(sparkLevels) - 1) / highest
+		}
+		line[i] = sparkLevels[level]
+	}
+	return string(line)
+}
+
+// barChart renders one labelled bar per period, scaled so the longest fills width
+func barChart(periods []periodCount, width int) string {
+	labelWidth, highest := 0, 0
+	for _, p := range periods {
+		labelWidth = max(labelWidth, lipgloss.Width(p.label))
+		highest = max(highest, p.count)
+	}
+	barWidth := width - labelWidth - 8
+	if barWidth < 5 {
+		barWidth = 5
+	}
+
+	s := strings.Builder{}
+	for _, p := range periods {
+		length := 0
+		if highest > 0 {
+			length = p.count * barWidth / highest
+		}
+		if p.count > 0 && length == 0 {
+			length = 1
+		}
+		label := helpTextStyle.Render(fmt.Sprintf("%-*s", labelWidth, p.label))
+		s.WriteString("    " + label + " " + countStyle.Render(strings.Repeat("█", length)) + " " + fmt.Sprintf("%d", p.count) + "\n")
+	}
+	return s.String()
+}
+
+// formatLeadTime renders an average lead time in days and hours, e.g. "3d 4h"
+func formatLeadTime(d time.Duration) string {
+	hours := int(d.Round(time.Hour) / time.Hour)
+	if hours < 24 {
+		return formatDuration(d)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
+
+// pluralDays returns "1 day" or "n days"
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// renderStatsView renders the stats dashboard for every completed todo, including
+// backups
+func (m Model) renderStatsView() string {
+	s := m.stats
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	chartWidth := min(width-4, 72)
+
+	b := strings.Builder{}
+	b.WriteString("  " + activeTabStyle.Render("STATS") + "\n\n")
+
+	if s.total == 0 {
+		b.WriteString("  " + infoMessageStyle.Render("No completed todos yet") + "\n\n")
+		b.WriteString("  " + helpTextStyle.Render("Press esc to exit stats view") + "\n")
+		return b.String()
+	}
+
+	figure := func(label, value string) string {
+		return headerStyle.Render(label+":") + " " + countStyle.Render(value)
+	}
+	b.WriteString("  " + figure("Today", fmt.Sprint(s.today)) + "   " + figure("This week", fmt.Sprint(s.thisWeek)) +
+		"   " + figure("This month", fmt.Sprint(s.thisMonth)) + "   " + figure("All time", fmt.Sprint(s.total)) + "\n")
+	lead := "n/a"
+	if s.averageLead > 0 {
+		lead = formatLeadTime(s.averageLead)
+	}
+	b.WriteString("  " + figure("Average lead time", lead) + "   " + figure("Current streak", pluralDays(s.currentStreak)) +
+		"   " + figure("Longest streak", pluralDays(s.longestStreak)) + "\n\n")
+
+	b.WriteString("  " + headerStyle.Render(fmt.Sprintf("Last %d days", statsTrend)) + "  " + countStyle.Render(sparkline(s.trend)) + "\n\n")
+
+	b.WriteString("  " + headerStyle.Render("Per day") + "\n")
+	b.WriteString(barChart(s.days, chartWidth) + "\n")
+	b.WriteString("  " + headerStyle.Render("Per week") + "\n")
+	b.WriteString(barChart(s.weeks, chartWidth) + "\n")
+	b.WriteString("  " + headerStyle.Render("Per month") + "\n")
+	b.WriteString(barChart(s.months, chartWidth) + "\n")
+
+	// Busiest weekdays, starting from the configured first day of the week
+	weekdays := make([]periodCount, 7)
+	for i := range weekdays {
+		day := time.Weekday((int(cfg.weekStart) + i) % 7)
+		weekdays[i] = periodCount{day.String(), s.weekdays[day]}
+	}
+	b.WriteString("  " + headerStyle.Render("By weekday") + "\n")
+	b.WriteString(barChart(weekdays, chartWidth) + "\n")
+
+	b.WriteString("  " + helpTextStyle.Render("Press esc to exit stats view, q to quit") + "\n")
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestComputeStats(t *testing.T) {
+	// Wednesday, with weeks starting on Sunday
+	now := time.Date(2026, 3, 11, 18, 0, 0, 0, time.Local)
+	completedDaysAgo := func(days, leadHours int) Todo {
+		completed := now.AddDate(0, 0, -days).Add(-time.Hour)
+		return Todo{CreatedAt: completed.Add(-time.Duration(leadHours) * time.Hour), CompletedAt: &completed}
+	}
+	todos := []Todo{
+		completedDaysAgo(0, 2),  // Today
+		completedDaysAgo(0, 4),  // Today
+		completedDaysAgo(1, 6),  // Tuesday
+		completedDaysAgo(3, 8),  // Sunday, the first day of this week
+		completedDaysAgo(12, 4), // Last month, two weeks ago
+		completedDaysAgo(13, 6),
+		completedDaysAgo(14, 6), // Also a Wednesday
+		{Text: "Never completed"},
+	}
+
+	s := computeStats(todos, now)
+	if s.total != 7 || s.today != 2 || s.thisWeek != 4 || s.thisMonth != 4 {
+		t.Errorf("total/today/week/month = %d/%d/%d/%d, want 7/2/4/4", s.total, s.today, s.thisWeek, s.thisMonth)
+	}
+	if s.averageLead != 36*time.Hour/7 {
+		t.Errorf("averageLead = %v, want %v", s.averageLead, 36*time.Hour/7)
+	}
+	if s.currentStreak != 2 || s.longestStreak != 3 {
+		t.Errorf("streaks = %d current, %d longest, want 2 and 3", s.currentStreak, s.longestStreak)
+	}
+	if s.weekdays[time.Wednesday] != 3 || s.weekdays[time.Tuesday] != 1 || s.weekdays[time.Monday] != 0 {
+		t.Errorf("weekdays = %v", s.weekdays)
+	}
+	if len(s.days) != statsDays || s.days[statsDays-1].count != 2 || s.days[statsDays-2].count != 1 {
+		t.Errorf("days = %v, want today last", s.days)
+	}
+	if len(s.months) != statsMonths || s.months[statsMonths-1].count != 4 || s.months[statsMonths-2].count != 3 {
+		t.Errorf("months = %v", s.months)
+	}
+	if s.weeks[statsWeeks-1].count != 4 || s.weeks[statsWeeks-2].count != 0 || s.weeks[statsWeeks-3].count != 3 {
+		t.Errorf("weeks = %v", s.weeks)
+	}
+}
+
+func TestStreaksAllowTodayWithoutCompletions(t *testing.T) {
+	today := time.Date(2026, 3, 11, 0, 0, 0, 0, time.Local)
+	perDay := map[time.Time]int{
+		today.AddDate(0, 0, -1): 1,
+		today.AddDate(0, 0, -2): 3,
+	}
+	if current, longest := streaks(perDay, today); current != 2 || longest != 2 {
+		t.Errorf("streaks() = %d, %d, want 2, 2", current, longest)
+	}
+	perDay = map[time.Time]int{today.AddDate(0, 0, -2): 1}
+	if current, _ := streaks(perDay, today); current != 0 {
+		t.Errorf("streaks() current = %d, want 0 after a missed day", current)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		counts   []int
+		expected string
+	}{
+		{[]int{0, 0, 0}, "▁▁▁"},
+		{[]int{0, 7, 14}, "▁▄█"},
+		{[]int{1, 2, 3, 4, 5, 6, 7, 8}, "▁▂▃▄▅▆▇█"},
+		{[]int{3, 1}, "█▃"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.counts); got != tt.expected {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.counts, got, tt.expected)
+		}
+	}
+}
+
+func TestUpdateStatsView(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	saveTodos(completedFile, []Todo{{Text: "Done today", CreatedAt: now.Add(-3 * time.Hour), CompletedAt: &now}})
+	os.MkdirAll("backup", 0755)
+	saveTodos("todo_completed_backup_2026-01-05_1.txt", []Todo{{Text: "Archived", CreatedAt: now.AddDate(0, 0, -9), CompletedAt: &now}})
+
+	m := Model{currentView: viewReady}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'%'}})
+	m = updated.(Model)
+	if !m.showingStats || m.stats.total != 2 {
+		t.Fatalf("showingStats = %v, total = %d, want the stats view counting backups", m.showingStats, m.stats.total)
+	}
+	view := m.View()
+	for _, want := range []string{"STATS", "All time: 2", "Current streak: 1 day", "Per week", "By weekday", "█"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("stats view should contain %q:\n%s", want, view)
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).showingStats {
+		t.Error("esc should close the stats view")
+	}
+}
@@ -2,7 +2,11 @@ package model
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,15 +15,60 @@ import (
 	"time"
 )
 
+// maxTodoLine is the longest line a todo file may hold; each todo is one line, which
+// grows with its updates, checklist and time log
+const maxTodoLine = 64 << 20
+
+// loadTodos reads todos from filename, returning an empty list if it cannot be opened
+// and what could be read if it can't be read to the end. Todos stored without an ID
+// are assigned one in memory (see backfillID).
 func loadTodos(filename string) []Todo {
-	file, err := os.Open(filename)
+	todos, _, _ := readTodos(filename)
+	return todos
+}
+
+// loadTodosWithIDs loads todos like loadTodos and, if any of them had to be assigned
+// an ID, writes the file back so the new IDs stay stable across runs. A file that
+// can't be read to the end is never written back, so no todos are lost.
+func loadTodosWithIDs(filename string) ([]Todo, error) {
+	todos, backfilled, err := readTodos(filename)
 	if err != nil {
-		return []Todo{}
+		return todos, err
+	}
+	if backfilled {
+		if err := saveTodos(filename, todos); err != nil {
+			return todos, err
+		}
+	}
+	return todos, nil
+}
+
+// readTodos parses a todo file, reporting whether any todo was missing an ID. A file
+// that doesn't exist holds no todos.
+func readTodos(filename string) ([]Todo, bool, error) {
+	file, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Todo{}, false, nil
+	} else if err != nil {
+		return []Todo{}, false, err
 	}
 	defer file.Close()
+	todos, backfilled, err := parseTodos(file, filename)
+	if err != nil {
+		return todos, backfilled, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	return todos, backfilled, nil
+}
 
+// parseTodos reads todos stored as JSON lines from a copy of filename, reporting
+// whether any todo was missing an ID. On a read error it returns the todos read so
+// far with the error.
+func parseTodos(r io.Reader, filename string) ([]Todo, bool, error) {
 	var todos []Todo
-	scanner := bufio.NewScanner(file)
+	backfilled := false
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTodoLine)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" {
@@ -31,35 +80,82 @@ func loadTodos(filename string) []Todo {
 					CreatedAt: time.Now(),
 				}
 			}
+			if todo.ID == "" {
+				todo.ID = backfillID(filename, line, seen[line])
+				seen[line]++
+				backfilled = true
+			}
 			todos = append(todos, todo)
 		}
 	}
-	return todos
+	return todos, backfilled, scanner.Err()
 }
 
+// backfillID returns the ID for the nth todo stored as line without one in filename.
+// It's derived from the line, so reading a file that can't be written back, such as
+// while read-only, gives its todos the same IDs every time. The file's name is part
+// of it, so the same line in two files doesn't give two todos one ID.
+func backfillID(filename, line string, n int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s:%d:%s", filepath.Base(filename), n, line))
+	return hex.EncodeToString(sum[:8])
+}
+
+// saveTodos writes todos as JSON lines. The data is written to a temporary file in
+// the same directory, synced to disk and then renamed over filename, so a crash or
+// full disk mid-write leaves the previous contents intact.
 func saveTodos(filename string, todos []Todo) error {
-	file, err := os.Create(filename)
+	var buf bytes.Buffer
+	for _, todo := range todos {
+		data, err := json.Marshal(todo)
+		if err != nil {
+			return fmt.Errorf("encoding todo %q: %w", todo.Text, err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	return writeFileAtomic(filename, buf.Bytes())
+}
+
+// writeFileAtomic replaces filename with data via a synced temporary file and rename
+func writeFileAtomic(filename string, data []byte) (err error) {
+	// Keep the existing file's permissions, defaulting to what os.Create would use
+	perm := os.FileMode(0644)
+	if info, statErr := os.Stat(filename); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	for _, todo := range todos {
-		data, err := json.Marshal(todo)
+	tmpName := tmp.Name()
+	defer func() {
 		if err != nil {
-			continue
+			tmp.Close()
+			os.Remove(tmpName)
 		}
-		fmt.Fprintln(writer, string(data))
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
 	}
-	return writer.Flush()
+	return os.Rename(tmpName, filename)
 }
 
 func backupCompletedTodos(todos []Todo) (string, error) {
 	// Generate backup filename with current date and number of todos
 	now := time.Now()
-	dateStr := now.Format("2006-01-02")
-	filename := fmt.Sprintf("todo_completed_backup_%s_%d.txt", dateStr, len(todos))
+	filename := archiveName(now.Format("2006-01-02"), len(todos))
 
 	// Save todos to backup file
 	if err := saveTodos(filename, todos); err != nil {
@@ -69,6 +165,11 @@ func backupCompletedTodos(todos []Todo) (string, error) {
 	return filename, nil
 }
 
+// archiveName returns the name of the backup file made on date holding count todos
+func archiveName(date string, count int) string {
+	return fmt.Sprintf("todo_completed_backup_%s_%d.txt", date, count)
+}
+
 // findBackupFiles finds all backup completed todo files in the current directory
 func findBackupFiles() ([]string, error) {
 	pattern := "todo_completed_backup_*.txt"
@@ -99,54 +200,3 @@ func loadAllCompletedTodos() []Todo {
 
 	return allTodos
 }
-
-// createBackups creates the backup directory and backs up all three todo files
-func createBackups() error {
-	// Create backup directory if it doesn't exist
-	backupDir := "backup"
-	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return err
-	}
-
-	// List of files to backup
-	files := []string{backlogFile, readyFile, completedFile}
-
-	// Copy each file to backup directory with .bak extension
-	for _, filename := range files {
-		// Skip if source file doesn't exist
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
-			continue
-		}
-
-		// Open source file
-		src, err := os.Open(filename)
-		if err != nil {
-			return err
-		}
-
-		// Create destination file (overwrites if exists)
-		dstPath := filepath.Join(backupDir, filename+".bak")
-		dst, err := os.Create(dstPath)
-		if err != nil {
-			closeErr := src.Close()
-			if closeErr != nil {
-				return fmt.Errorf("error while closing backup file: %v while handling create error: %v",
-					closeErr, err)
-			}
-			return err
-		}
-
-		// Copy contents
-		_, err = io.Copy(dst, src)
-		err = src.Close()
-		if err != nil {
-			return fmt.Errorf("error while closing %s: %v", filename, err)
-		}
-		err = dst.Close()
-		if err != nil {
-			return fmt.Errorf("error while closing %s: %v", dstPath, err)
-		}
-	}
-
-	return nil
-}
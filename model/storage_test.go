@@ -3,6 +3,7 @@ package model
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -153,6 +154,92 @@ func TestLoadTodosNonExistentFile(t *testing.T) {
 	}
 }
 
+func TestLoadTodosLongLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_todos.txt")
+
+	// A todo longer than bufio.Scanner's default 64KB limit, followed by another
+	long := Todo{ID: "1", Text: "Long", Updates: []TodoUpdate{{Text: strings.Repeat("x", 100*1024)}}}
+	if err := saveTodos(tmpFile, []Todo{long, {ID: "2", Text: "After"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	todos, err := loadTodosWithIDs(tmpFile)
+	if err != nil {
+		t.Fatalf("loadTodosWithIDs() error = %v", err)
+	}
+	if len(todos) != 2 || todos[1].Text != "After" || len(todos[0].Updates[0].Text) != 100*1024 {
+		t.Errorf("got %d todos, want both read in full", len(todos))
+	}
+}
+
+func TestLoadTodosAssignsIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_todos.txt")
+
+	content := `Duplicate task
+Duplicate task
+{"id":"abc123","text":"Has ID","created_at":"2024-01-01T10:00:00Z"}`
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Without writing the file back, each read still assigns the same IDs
+	first, second := loadTodos(tmpFile), loadTodos(tmpFile)
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Errorf("Todo[%d].ID = %q on one read and %q on the next", i, first[i].ID, second[i].ID)
+		}
+	}
+
+	todos, err := loadTodosWithIDs(tmpFile)
+	if err != nil {
+		t.Fatalf("loadTodosWithIDs() error = %v", err)
+	}
+	if len(todos) != 3 {
+		t.Fatalf("Expected 3 todos, got %d", len(todos))
+	}
+	if todos[0].ID == "" || todos[1].ID == "" {
+		t.Error("Legacy todos should be assigned IDs")
+	}
+	if todos[0].ID == todos[1].ID {
+		t.Error("Duplicate legacy todos should get distinct IDs")
+	}
+	if todos[2].ID != "abc123" {
+		t.Errorf("Existing ID = %q, want %q", todos[2].ID, "abc123")
+	}
+
+	// Assigned IDs are persisted, so loading again yields the same IDs
+	reloaded := loadTodos(tmpFile)
+	for i := range todos {
+		if reloaded[i].ID != todos[i].ID {
+			t.Errorf("Todo[%d].ID after reload = %q, want %q", i, reloaded[i].ID, todos[i].ID)
+		}
+	}
+}
+
+func TestLoadTodosWithIDsLeavesCompleteFilesUntouched(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_todos.txt")
+
+	content := `{"id":"abc123","text":"Has ID","created_at":"2024-01-01T10:00:00Z"}` + "\n"
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	if _, err := loadTodosWithIDs(tmpFile); err != nil {
+		t.Fatalf("loadTodosWithIDs() error = %v", err)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("File was rewritten: got %q, want %q", string(data), content)
+	}
+}
+
 func TestSaveTodos(t *testing.T) {
 	now := time.Now()
 	completedTime := now.Add(-1 * time.Hour)
@@ -192,7 +279,7 @@ func TestSaveTodos(t *testing.T) {
 			todos: []Todo{
 				{
 					Text:      "Task with desc",
-					Updates:   []string{"desc1", "desc2"},
+					Updates:   textUpdates("desc1", "desc2"),
 					CreatedAt: now,
 				},
 			},
@@ -265,6 +352,70 @@ func TestSaveTodos(t *testing.T) {
 	}
 }
 
+func TestSaveTodosAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "test_save.txt")
+
+	original := []Todo{{Text: "Original task", CreatedAt: time.Now()}}
+	if err := saveTodos(tmpFile, original); err != nil {
+		t.Fatalf("saveTodos() error = %v", err)
+	}
+	if err := os.Chmod(tmpFile, 0600); err != nil {
+		t.Fatalf("Failed to chmod test file: %v", err)
+	}
+
+	// Years beyond 9999 cannot be encoded as JSON timestamps
+	invalid := []Todo{
+		{Text: "Valid task", CreatedAt: time.Now()},
+		{Text: "Invalid task", CreatedAt: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	err := saveTodos(tmpFile, invalid)
+	if err == nil {
+		t.Fatal("saveTodos() should return an error when a todo cannot be encoded")
+	}
+	if !strings.Contains(err.Error(), "Invalid task") {
+		t.Errorf("error = %q, want to name the failing todo", err.Error())
+	}
+
+	// The original file must be untouched after a failed save
+	loaded := loadTodos(tmpFile)
+	if len(loaded) != 1 || loaded[0].Text != "Original task" {
+		t.Errorf("file contents after failed save = %v, want original todo", loaded)
+	}
+
+	// A successful save replaces the file and keeps its permissions
+	if err := saveTodos(tmpFile, []Todo{{Text: "Replacement", CreatedAt: time.Now()}}); err != nil {
+		t.Fatalf("saveTodos() error = %v", err)
+	}
+	info, err := os.Stat(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to stat saved file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// No temporary files should be left behind
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory contains %v, want only the saved file", names)
+	}
+}
+
+func TestSaveTodosMissingDirectory(t *testing.T) {
+	err := saveTodos(filepath.Join(t.TempDir(), "missing", "todos.txt"), []Todo{{Text: "task", CreatedAt: time.Now()}})
+	if err == nil {
+		t.Error("saveTodos() should return an error when the directory does not exist")
+	}
+}
+
 func TestBackupCompletedTodos(t *testing.T) {
 	now := time.Now()
 	completedTime := now.Add(-1 * time.Hour)
@@ -693,7 +844,11 @@ func TestCreateBackups(t *testing.T) {
 
 			// Verify backup files exist and match original content
 			for filename, originalTodos := range tt.setupFiles {
-				backupPath := filepath.Join("backup", filename+".bak")
+				snapshots, _ := listSnapshots()
+				if len(snapshots) != 1 {
+					t.Fatalf("got %d snapshots, want 1", len(snapshots))
+				}
+				backupPath := filepath.Join(snapshots[0].dir(), filename)
 
 				// Check file exists
 				if _, err := os.Stat(backupPath); os.IsNotExist(err) {
@@ -720,7 +875,7 @@ func TestCreateBackups(t *testing.T) {
 	}
 }
 
-func TestCreateBackupsOverwrite(t *testing.T) {
+func TestCreateBackupsKeepsSnapshots(t *testing.T) {
 	tmpDir := t.TempDir()
 	originalWd, _ := os.Getwd()
 	defer os.Chdir(originalWd)
@@ -742,6 +897,14 @@ func TestCreateBackupsOverwrite(t *testing.T) {
 		t.Fatalf("First createBackups() error = %v", err)
 	}
 
+	// Starting again without changes takes no new snapshot
+	if err := createBackups(); err != nil {
+		t.Fatalf("Second createBackups() error = %v", err)
+	}
+	if snapshots, _ := listSnapshots(); len(snapshots) != 1 {
+		t.Errorf("got %d snapshots, want 1 when nothing changed", len(snapshots))
+	}
+
 	// Modify the original file
 	updatedTodos := []Todo{
 		{Text: "Updated task 1", CreatedAt: now},
@@ -752,26 +915,20 @@ func TestCreateBackupsOverwrite(t *testing.T) {
 		t.Fatalf("Failed to update file: %v", err)
 	}
 
-	// Create backups again (should overwrite)
+	// Create backups again (should keep the first snapshot)
 	err = createBackups()
 	if err != nil {
-		t.Fatalf("Second createBackups() error = %v", err)
+		t.Fatalf("Third createBackups() error = %v", err)
 	}
 
-	// Verify backup has the updated content
-	backupPath := filepath.Join("backup", readyFile+".bak")
-	backupTodos := loadTodos(backupPath)
-
-	if len(backupTodos) != len(updatedTodos) {
-		t.Errorf("backup should have %d todos after overwrite, got %d", len(updatedTodos), len(backupTodos))
+	snapshots, _ := listSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snapshots))
 	}
-
-	for i, updated := range updatedTodos {
-		if i >= len(backupTodos) {
-			continue
-		}
-		if backupTodos[i].Text != updated.Text {
-			t.Errorf("backup todo[%d].Text = %q, want %q (overwrite failed)", i, backupTodos[i].Text, updated.Text)
-		}
+	if got := readSnapshotTodos(snapshots[0], readyFile); len(got) != len(updatedTodos) {
+		t.Errorf("latest snapshot has %d todos, want %d", len(got), len(updatedTodos))
+	}
+	if got := readSnapshotTodos(snapshots[1], readyFile); len(got) != 1 || got[0].Text != "Initial task" {
+		t.Errorf("earlier snapshot = %+v, want the initial todo kept", got)
 	}
 }
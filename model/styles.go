@@ -2,33 +2,97 @@ package model
 
 import "github.com/charmbracelet/lipgloss"
 
-// Color styles
+// Color styles, set from the active theme by applyTheme
 var (
 	// View tabs
-	activeTabStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("117")).Background(lipgloss.Color("236")).Padding(0, 1)
-	inactiveTabStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Padding(0, 1)
+	activeTabStyle   lipgloss.Style
+	inactiveTabStyle lipgloss.Style
 
 	// Cursor and items
-	cursorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("81")).Bold(true)
-	todoTextStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("255"))
-	timestampStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
-	updateStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Italic(true)
-	completeNoteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("120")).Bold(true)
+	cursorStyle       lipgloss.Style
+	todoTextStyle     lipgloss.Style
+	timestampStyle    lipgloss.Style
+	timerStyle        lipgloss.Style
+	updateStyle       lipgloss.Style
+	completeNoteStyle lipgloss.Style
+	dueStyle          lipgloss.Style
+	dueTodayStyle     lipgloss.Style
+	overdueStyle      lipgloss.Style
+	tagStyle          lipgloss.Style
+	searchMatchStyle  lipgloss.Style
+
+	// Priority badges, most urgent first (see priorities)
+	priorityStyles []lipgloss.Style
 
 	// Headers and sections
-	headerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("75")).Bold(true)
-	countStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("120")).Bold(true)
+	headerStyle lipgloss.Style
+	countStyle  lipgloss.Style
 
 	// Input prompts
-	promptStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Bold(true)
-	inputCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("81")).Bold(true)
-	helpTextStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+	promptStyle      lipgloss.Style
+	inputCursorStyle lipgloss.Style
+	helpTextStyle    lipgloss.Style
 
 	// Messages
-	successMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("120")).Bold(true)
-	errorMessageStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
-	infoMessageStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("117"))
+	successMessageStyle lipgloss.Style
+	errorMessageStyle   lipgloss.Style
+	warningMessageStyle lipgloss.Style
+	infoMessageStyle    lipgloss.Style
 
 	// Commands
-	commandStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+	commandStyle lipgloss.Style
 )
+
+func init() {
+	applyTheme(themes["dark"])
+}
+
+// applyTheme rebuilds every style from the colours of t. Highlights without a
+// background colour are shown in reverse video instead.
+func applyTheme(t theme) {
+	fg := func(color string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+	}
+	highlight := func(text, background string) lipgloss.Style {
+		if background == "" {
+			return fg(text).Reverse(true)
+		}
+		return fg(text).Background(lipgloss.Color(background))
+	}
+
+	activeTabStyle = highlight(t.Accent, t.TabBackground).Bold(true).Padding(0, 1)
+	inactiveTabStyle = fg(t.InactiveTab).Padding(0, 1)
+
+	cursorStyle = fg(t.Highlight).Bold(true)
+	todoTextStyle = fg(t.Text)
+	timestampStyle = fg(t.Subtle)
+	timerStyle = fg(t.Warning).Bold(true)
+	updateStyle = fg(t.Accent).Italic(true)
+	completeNoteStyle = fg(t.Success).Bold(true)
+	dueStyle = fg(t.Due)
+	dueTodayStyle = fg(t.Warning).Bold(true)
+	overdueStyle = fg(t.Error).Bold(true)
+	tagStyle = fg(t.Tag)
+	searchMatchStyle = highlight(t.MatchText, t.MatchBackground).Bold(true)
+
+	priorityStyles = []lipgloss.Style{
+		highlight(t.BadgeText, t.P0).Bold(true),
+		highlight(t.BadgeText, t.P1).Bold(true),
+		highlight(t.BadgeText, t.P2),
+		highlight(t.BadgeText, t.P3),
+	}
+
+	headerStyle = fg(t.Header).Bold(true)
+	countStyle = fg(t.Success).Bold(true)
+
+	promptStyle = fg(t.Accent).Bold(true)
+	inputCursorStyle = fg(t.Highlight).Bold(true)
+	helpTextStyle = fg(t.Muted).Italic(true)
+
+	successMessageStyle = fg(t.Success).Bold(true)
+	errorMessageStyle = fg(t.Error).Bold(true)
+	warningMessageStyle = fg(t.Warning).Bold(true)
+	infoMessageStyle = fg(t.Accent)
+
+	commandStyle = fg(t.Header)
+}
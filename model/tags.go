@@ -0,0 +1,164 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// isTagRune reports whether r can appear in a tag name
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+// normalizeTag lowercases a tag and strips a leading '#' and any invalid characters
+func normalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.TrimFunc(tag, func(r rune) bool { return !isTagRune(r) }))
+}
+
+// parseTags extracts #hashtags from todo text, lowercased and without duplicates
+func parseTags(text string) []string {
+	var tags []string
+	for _, word := range strings.Fields(text) {
+		if !strings.HasPrefix(word, "#") {
+			continue
+		}
+		// Stop at the first character that can't be part of a tag, e.g. "#infra," or "#ops."
+		name := strings.TrimPrefix(word, "#")
+		if end := strings.IndexFunc(name, func(r rune) bool { return !isTagRune(r) }); end >= 0 {
+			name = name[:end]
+		}
+		if tag := normalizeTag(name); tag != "" {
+			tags = mergeTags(tags, []string{tag})
+		}
+	}
+	return tags
+}
+
+// parseTagInput parses the tag prompt: tags separated by commas or spaces, '#' optional
+func parseTagInput(input string) []string {
+	var tags []string
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	for _, field := range fields {
+		if tag := normalizeTag(field); tag != "" {
+			tags = mergeTags(tags, []string{tag})
+		}
+	}
+	return tags
+}
+
+// mergeTags returns tags followed by each tag in extra that isn't already present
+func mergeTags(tags, extra []string) []string {
+	for _, tag := range extra {
+		if !hasTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// hasTag reports whether tags contains tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTags renders tags as "#a #b"
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
+
+// extraTags returns the todo's tags that don't appear as #hashtags in its text
+func extraTags(todo Todo) []string {
+	inText := parseTags(todo.Text)
+	var extra []string
+	for _, tag := range todo.Tags {
+		if !hasTag(inText, tag) {
+			extra = append(extra, tag)
+		}
+	}
+	return extra
+}
+
+// countTags returns each tag used by todos with how many todos carry it, most used first
+func countTags(todos []Todo) []tagCount {
+	counts := map[string]int{}
+	for _, todo := range todos {
+		for _, tag := range todo.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]tagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, tagCount{tag: tag, count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].count != result[j].count {
+			return result[i].count > result[j].count
+		}
+		return result[i].tag < result[j].tag
+	})
+	return result
+}
+
+// tagCount is the number of todos carrying a tag
+type tagCount struct {
+	tag   string
+	count int
+}
+
+// matchesTagFilter reports whether todo has any of the tags in the active filter.
+// Every todo matches when no filter is set.
+func (m *Model) matchesTagFilter(todo Todo) bool {
+	if len(m.tagFilter) == 0 {
+		return true
+	}
+	for _, tag := range m.tagFilter {
+		if hasTag(todo.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTagFilter restricts every list to todos with any of tags, or clears the filter if empty
+func (m *Model) setTagFilter(tags []string) {
+	m.tagFilter = tags
+	m.cursor = 0
+	m.completedPage = 0
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+	m.updateDisplayedCompleted()
+}
+
+// renderTextWithTags renders a line of todo text, styling any #hashtags that are tags
+func renderTextWithTags(line string, tags []string) string {
+	if len(tags) == 0 || !strings.Contains(line, "#") {
+		return todoTextStyle.Render(line)
+	}
+
+	var sb strings.Builder
+	words := strings.Split(line, " ")
+	for i, word := range words {
+		if i > 0 {
+			sb.WriteString(todoTextStyle.Render(" "))
+		}
+		if strings.HasPrefix(word, "#") && len(parseTags(word)) == 1 && hasTag(tags, parseTags(word)[0]) {
+			sb.WriteString(tagStyle.Render(word))
+		} else if word != "" {
+			sb.WriteString(todoTextStyle.Render(word))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,266 @@
+package model
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+		update   string
+	}{
+		{"no tags", "Fix the login bug", nil, "Plain text has no tags"},
+		{"single tag", "Deploy #infra changes", []string{"infra"}, "Hashtag anywhere in the text"},
+		{"lowercased", "Call #Ops", []string{"ops"}, "Tags are case-insensitive"},
+		{"duplicates", "#infra and #INFRA", []string{"infra"}, "Duplicate tags are collapsed"},
+		{"trailing punctuation", "Ship it #release, then #ops.", []string{"release", "ops"}, "Punctuation ends a tag"},
+		{"dashes and underscores", "#on-call #q3_goals", []string{"on-call", "q3_goals"}, "Dashes and underscores are allowed"},
+		{"bare hash", "Issue # 42", nil, "A lone # is not a tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTags(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseTags(%q) = %v, want %v (%s)", tt.text, got, tt.expected, tt.update)
+			}
+		})
+	}
+}
+
+func TestParseTagInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"infra, ops", []string{"infra", "ops"}},
+		{"#infra #ops", []string{"infra", "ops"}},
+		{"  Infra,,infra  ", []string{"infra"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseTagInput(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parseTagInput(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := mergeTags([]string{"infra"}, []string{"ops", "infra"})
+	if !reflect.DeepEqual(got, []string{"infra", "ops"}) {
+		t.Errorf("mergeTags() = %v, want [infra ops]", got)
+	}
+}
+
+func TestExtraTags(t *testing.T) {
+	todo := Todo{Text: "Deploy #infra", Tags: []string{"infra", "q3"}}
+	if got := extraTags(todo); !reflect.DeepEqual(got, []string{"q3"}) {
+		t.Errorf("extraTags() = %v, want [q3]", got)
+	}
+}
+
+func TestCountTags(t *testing.T) {
+	todos := []Todo{
+		{Tags: []string{"ops", "infra"}},
+		{Tags: []string{"infra"}},
+		{},
+	}
+	got := countTags(todos)
+	expected := []tagCount{{tag: "infra", count: 2}, {tag: "ops", count: 1}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("countTags() = %v, want %v", got, expected)
+	}
+}
+
+func TestRenderTextWithTags(t *testing.T) {
+	result := renderTextWithTags("Deploy #infra now", []string{"infra"})
+	if !strings.Contains(result, "#infra") || !strings.Contains(result, "Deploy") || !strings.Contains(result, "now") {
+		t.Errorf("renderTextWithTags() = %q, want all words preserved", result)
+	}
+
+	if renderTextWithTags("plain", nil) != todoTextStyle.Render("plain") {
+		t.Error("renderTextWithTags() without tags should render plain text")
+	}
+}
+
+func TestTagFilterRestrictsLists(t *testing.T) {
+	now := time.Now()
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "Deploy", Tags: []string{"infra"}, CreatedAt: now},
+			{ID: "2", Text: "Write docs", Tags: []string{"docs"}, CreatedAt: now},
+			{ID: "3", Text: "Rotate keys", Tags: []string{"ops", "infra"}, CreatedAt: now},
+		},
+		completed: []Todo{
+			{ID: "4", Text: "Old deploy", Tags: []string{"infra"}, CreatedAt: now, CompletedAt: &now},
+			{ID: "5", Text: "Old docs", Tags: []string{"docs"}, CreatedAt: now, CompletedAt: &now},
+		},
+	}
+
+	m.setTagFilter([]string{"infra"})
+	list := m.getCurrentList()
+	if len(list) != 2 || list[0].ID != "1" || list[1].ID != "3" {
+		t.Errorf("filtered ready = %v, want todos 1 and 3", list)
+	}
+	if len(m.displayedCompleted) != 1 || m.displayedCompleted[0].ID != "4" {
+		t.Errorf("filtered completed = %v, want todo 4", m.displayedCompleted)
+	}
+
+	// Any of several tags matches
+	m.setTagFilter([]string{"docs", "ops"})
+	if list := m.getCurrentList(); len(list) != 2 || list[0].ID != "2" || list[1].ID != "3" {
+		t.Errorf("filtered ready for docs/ops = %v, want todos 2 and 3", list)
+	}
+
+	m.setTagFilter(nil)
+	if len(m.getCurrentList()) != 3 {
+		t.Error("clearing the filter should show every todo")
+	}
+}
+
+func TestTagFilterActionsUseStoredTodo(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "Deploy", Tags: []string{"infra"}, CreatedAt: now},
+			{ID: "2", Text: "Write docs", Tags: []string{"docs"}, CreatedAt: now},
+			{ID: "3", Text: "Rotate keys", Tags: []string{"infra"}, CreatedAt: now},
+		},
+	}
+	m.setTagFilter([]string{"infra"})
+
+	// J swaps with the next visible todo, skipping hidden ones
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	if m.ready[0].ID != "3" || m.ready[1].ID != "2" || m.ready[2].ID != "1" {
+		t.Errorf("ready after J = %v, want order 3, 2, 1", m.ready)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor after J = %d, want 1", m.cursor)
+	}
+
+	// x completes the todo under the cursor, not the hidden one at the same index
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.completed) != 1 || m.completed[0].ID != "1" {
+		t.Errorf("completed after x = %v, want todo 1", m.completed)
+	}
+	if m.cursor != 0 {
+		t.Errorf("cursor after x = %d, want 0", m.cursor)
+	}
+
+	// d deletes the selected visible todo
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if len(m.ready) != 1 || m.ready[0].ID != "2" {
+		t.Errorf("ready after delete = %v, want only the hidden todo", m.ready)
+	}
+}
+
+func TestUpdateEditTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog:     []Todo{{ID: "1", Text: "Deploy #infra", Tags: []string{"infra"}, CreatedAt: time.Now()}},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = updated.(Model)
+	if !m.editingTags || m.newTags != "infra" {
+		t.Fatalf("editingTags/newTags = %v/%q, want prompt prefilled with 'infra'", m.editingTags, m.newTags)
+	}
+
+	m.newTags = "infra, Q3 #ops"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !reflect.DeepEqual(m.backlog[0].Tags, []string{"infra", "q3", "ops"}) {
+		t.Errorf("Tags = %v, want [infra q3 ops]", m.backlog[0].Tags)
+	}
+	if saved := loadTodos(backlogFile); len(saved) != 1 || len(saved[0].Tags) != 3 {
+		t.Errorf("saved tags = %v, want 3 tags", saved)
+	}
+	if !strings.Contains(m.View(), "#q3 #ops") {
+		t.Error("View should list tags that aren't in the todo text")
+	}
+
+	// Esc cancels without changing tags
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.editingTags || len(m.backlog[0].Tags) != 3 {
+		t.Error("Esc should cancel tag editing")
+	}
+}
+
+func TestUpdateTagFilterPrompt(t *testing.T) {
+	now := time.Now()
+	m := Model{
+		currentView: viewBacklog,
+		backlog: []Todo{
+			{ID: "1", Text: "Deploy", Tags: []string{"infra"}, CreatedAt: now},
+			{ID: "2", Text: "Write docs", Tags: []string{"docs"}, CreatedAt: now},
+		},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(Model)
+	if !m.filteringTags {
+		t.Fatal("'f' should open the tag filter prompt")
+	}
+	for _, r := range "docs" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if !reflect.DeepEqual(m.tagFilter, []string{"docs"}) {
+		t.Errorf("tagFilter = %v, want [docs]", m.tagFilter)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Filter:") || strings.Contains(view, "Deploy") {
+		t.Error("View should show the filter and hide non-matching todos")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = updated.(Model)
+	if len(m.tagFilter) != 0 || m.message != "Filter cleared" {
+		t.Errorf("tagFilter/message after F = %v/%q, want cleared", m.tagFilter, m.message)
+	}
+}
+
+func TestUpdateAddParsesTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{currentView: viewReady, adding: true, newTodo: "review #infra PR"}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if len(m.ready) != 1 || !reflect.DeepEqual(m.ready[0].Tags, []string{"infra"}) {
+		t.Errorf("ready = %v, want new todo tagged infra", m.ready)
+	}
+}
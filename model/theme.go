@@ -0,0 +1,209 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// theme is a colour scheme. Colours are 256-colour numbers ("0"-"255") or hex
+// ("#268bd2"); an empty colour leaves the terminal's own colour in place.
+type theme struct {
+	Text            string // Todo text
+	Muted           string // Help text
+	InactiveTab     string // Inactive tabs
+	Subtle          string // Timestamps
+	Accent          string // Active tab, prompts, updates and info messages
+	Highlight       string // Cursors
+	Header          string // Headers and command lists
+	Success         string // Counts, complete notes and success messages
+	Error           string // Overdue dates and errors
+	Warning         string // Todos due today
+	Due             string // Other due dates
+	Tag             string // Tags
+	TabBackground   string // Behind the active tab, reverse video if empty
+	MatchText       string // Search matches
+	MatchBackground string // Behind search matches, reverse video if empty
+	BadgeText       string // Priority badges
+	P0              string // Behind the P0 badge, reverse video if empty
+	P1              string
+	P2              string
+	P3              string
+}
+
+// themes are the built-in colour schemes
+var themes = map[string]theme{
+	"dark": {
+		Text: "255", Muted: "241", InactiveTab: "240", Subtle: "243", Accent: "117", Highlight: "81", Header: "75",
+		Success: "120", Error: "203", Warning: "214", Due: "180", Tag: "213",
+		TabBackground: "236", MatchText: "232", MatchBackground: "221",
+		BadgeText: "232", P0: "203", P1: "214", P2: "221", P3: "248",
+	},
+	"light": {
+		Text: "235", Muted: "243", InactiveTab: "243", Subtle: "240", Accent: "25", Highlight: "31", Header: "24",
+		Success: "28", Error: "160", Warning: "166", Due: "94", Tag: "127",
+		TabBackground: "254", MatchText: "232", MatchBackground: "222",
+		BadgeText: "232", P0: "210", P1: "215", P2: "222", P3: "250",
+	},
+	// Only the 16 standard colours, on top of the terminal's own foreground
+	"high-contrast": {
+		Success: "2", Error: "1", Warning: "3", Due: "3", Tag: "5", Highlight: "6", Accent: "6",
+		BadgeText: "0", P0: "1", P1: "3", P2: "6",
+	},
+	// No colours: emphasis comes from bold, italic and reverse video alone
+	"monochrome": {},
+}
+
+// themeRoles maps the colour names used in the config file to the fields of t
+func (t *theme) themeRoles() map[string]*string {
+	return map[string]*string{
+		"text":             &t.Text,
+		"muted":            &t.Muted,
+		"inactive_tab":     &t.InactiveTab,
+		"subtle":           &t.Subtle,
+		"accent":           &t.Accent,
+		"highlight":        &t.Highlight,
+		"header":           &t.Header,
+		"success":          &t.Success,
+		"error":            &t.Error,
+		"warning":          &t.Warning,
+		"due":              &t.Due,
+		"tag":              &t.Tag,
+		"tab_background":   &t.TabBackground,
+		"match_text":       &t.MatchText,
+		"match_background": &t.MatchBackground,
+		"badge_text":       &t.BadgeText,
+		"p0":               &t.P0,
+		"p1":               &t.P1,
+		"p2":               &t.P2,
+		"p3":               &t.P3,
+	}
+}
+
+// hexColorPattern matches "#rgb" and "#rrggbb" colours
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether color is empty, a 256-colour number or a hex colour
+func validColor(color string) bool {
+	if color == "" || hexColorPattern.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// parseThemes builds user-defined themes from the config file. Each one starts from
+// the built-in theme named by its "base" entry (dark by default) and overrides the
+// colours it lists. It returns a problem for each invalid entry.
+func parseThemes(raw map[string]map[string]string) (map[string]theme, []string) {
+	var problems []string
+	custom := map[string]theme{}
+
+	// Report problems in a stable order
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := themes[name]; ok || name == "auto" {
+			problems = append(problems, fmt.Sprintf("themes: %q is a built-in theme name; pick another", name))
+			continue
+		}
+		base := "dark"
+		if b, ok := raw[name]["base"]; ok {
+			base = b
+		}
+		t, ok := themes[base]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("themes: %s has unknown base theme %q", name, base))
+			continue
+		}
+
+		roles := t.themeRoles()
+		colors := make([]string, 0, len(raw[name]))
+		for role := range raw[name] {
+			colors = append(colors, role)
+		}
+		sort.Strings(colors)
+		for _, role := range colors {
+			color := raw[name][role]
+			field, ok := roles[role]
+			switch {
+			case role == "base":
+			case !ok:
+				problems = append(problems, fmt.Sprintf("themes: %s has unknown colour %q", name, role))
+			case !validColor(color):
+				problems = append(problems, fmt.Sprintf("themes: %s.%s must be a colour number 0-255 or #rrggbb, got %q", name, role, color))
+			default:
+				*field = color
+			}
+		}
+		// Inactive tabs follow muted unless given their own colour
+		if muted, ok := raw[name]["muted"]; ok && validColor(muted) {
+			if _, ok := raw[name]["inactive_tab"]; !ok {
+				t.InactiveTab = muted
+			}
+		}
+		custom[name] = t
+	}
+	return custom, problems
+}
+
+// validThemeName returns a problem if name isn't "auto", a built-in theme or one of
+// custom, or "" if it is
+func validThemeName(name string, custom map[string]theme) string {
+	if _, ok := themes[name]; ok || name == "auto" {
+		return ""
+	}
+	if _, ok := custom[name]; ok {
+		return ""
+	}
+	builtIn := make([]string, 0, len(themes))
+	for n := range themes {
+		builtIn = append(builtIn, n)
+	}
+	sort.Strings(builtIn)
+	return fmt.Sprintf("theme %q doesn't exist; use auto, %s or one defined under themes", name, strings.Join(builtIn, ", "))
+}
+
+// pickTheme chooses the theme to use. "auto" (or no theme) picks monochrome when
+// NO_COLOR is set or the terminal has no colours, high-contrast when it only has the
+// 16 standard colours, and otherwise dark or light to suit the terminal background.
+func pickTheme(name string, custom map[string]theme, noColor bool, profile termenv.Profile, darkBackground func() bool) theme {
+	if t, ok := custom[name]; ok {
+		return t
+	}
+	if t, ok := themes[name]; ok {
+		return t
+	}
+	switch {
+	case noColor || profile == termenv.Ascii:
+		return themes["monochrome"]
+	case profile == termenv.ANSI:
+		return themes["high-contrast"]
+	case darkBackground():
+		return themes["dark"]
+	default:
+		return themes["light"]
+	}
+}
+
+// UseTheme applies the configured theme to the UI. Call it just before starting the
+// TUI, as picking a theme automatically asks the terminal for its background colour.
+func UseTheme() {
+	noColor := os.Getenv("NO_COLOR") != ""
+	if noColor && cfg.theme != "auto" {
+		// NO_COLOR turns off colours lipgloss would pick by itself, but a theme chosen in
+		// the config file still gets the colours the terminal supports
+		lipgloss.SetColorProfile(termenv.NewOutput(os.Stdout).ColorProfile())
+	}
+	applyTheme(pickTheme(cfg.theme, cfg.themes, noColor, lipgloss.ColorProfile(), lipgloss.HasDarkBackground))
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestPickTheme(t *testing.T) {
+	custom := map[string]theme{"solarized": {Text: "#657b83"}}
+	dark := func() bool { return true }
+	light := func() bool { return false }
+
+	tests := []struct {
+		name     string
+		theme    string
+		noColor  bool
+		profile  termenv.Profile
+		dark     func() bool
+		expected theme
+	}{
+		{"auto dark background", "auto", false, termenv.ANSI256, dark, themes["dark"]},
+		{"auto light background", "auto", false, termenv.TrueColor, light, themes["light"]},
+		{"auto 16 colours", "auto", false, termenv.ANSI, dark, themes["high-contrast"]},
+		{"auto no colours", "auto", false, termenv.Ascii, dark, themes["monochrome"]},
+		{"auto NO_COLOR", "auto", true, termenv.TrueColor, dark, themes["monochrome"]},
+		{"named theme beats detection", "light", true, termenv.TrueColor, dark, themes["light"]},
+		{"custom theme", "solarized", false, termenv.TrueColor, dark, custom["solarized"]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickTheme(tt.theme, custom, tt.noColor, tt.profile, tt.dark); got != tt.expected {
+				t.Errorf("pickTheme(%q) = %+v, want %+v", tt.theme, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseThemes(t *testing.T) {
+	custom, problems := parseThemes(map[string]map[string]string{
+		"solarized": {"base": "light", "subtle": "#93a1a1", "muted": "245"},
+		"plain":     {},
+	})
+	if len(problems) != 0 {
+		t.Fatalf("parseThemes() problems = %v", problems)
+	}
+	solarized := custom["solarized"]
+	if solarized.Subtle != "#93a1a1" || solarized.Muted != "245" || solarized.Accent != themes["light"].Accent {
+		t.Errorf("solarized = %+v, want light with subtle and muted overridden", solarized)
+	}
+	if solarized.InactiveTab != "245" {
+		t.Errorf("solarized inactive tab = %q, want it to follow muted", solarized.InactiveTab)
+	}
+	if themes["dark"].InactiveTab != "240" || themes["dark"].Muted != "241" {
+		t.Error("the dark theme should keep the original inactive tab and help text colours")
+	}
+	if custom["plain"] != themes["dark"] {
+		t.Errorf("plain = %+v, want the dark theme", custom["plain"])
+	}
+
+	_, problems = parseThemes(map[string]map[string]string{
+		"dark":  {},
+		"mine":  {"base": "sepia", "text": "1"},
+		"other": {"txt": "1", "error": "300", "tag": "#12345"},
+	})
+	wanted := []string{
+		`"dark" is a built-in theme name`,
+		`mine has unknown base theme "sepia"`,
+		`other.error must be a colour number`,
+		`other.tag must be a colour number`,
+		`other has unknown colour "txt"`,
+	}
+	all := strings.Join(problems, "\n")
+	for _, want := range wanted {
+		if !strings.Contains(all, want) {
+			t.Errorf("parseThemes() problems = %q, want %q", all, want)
+		}
+	}
+}
+
+func TestParseConfigTheme(t *testing.T) {
+	loaded, err := parseConfig([]byte(`{"theme": "solarized", "themes": {"solarized": {"base": "light", "subtle": "246"}}}`))
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	if loaded.theme != "solarized" || loaded.themes["solarized"].Subtle != "246" {
+		t.Errorf("theme = %q, themes = %+v, want the custom theme", loaded.theme, loaded.themes)
+	}
+
+	if _, err := parseConfig([]byte(`{"theme": "neon"}`)); err == nil || !strings.Contains(err.Error(), `theme "neon" doesn't exist`) {
+		t.Errorf("parseConfig() error = %v, want unknown theme", err)
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	defer applyTheme(themes["dark"])
+
+	// Without colours, highlights fall back to reverse video so they stay visible
+	applyTheme(themes["monochrome"])
+	if !searchMatchStyle.GetReverse() || !activeTabStyle.GetReverse() || !priorityStyles[0].GetReverse() {
+		t.Error("monochrome highlights should use reverse video")
+	}
+
+	applyTheme(themes["light"])
+	if searchMatchStyle.GetReverse() {
+		t.Error("light search matches should use a background colour")
+	}
+	if timestampStyle.GetForeground() != lipgloss.Color(themes["light"].Subtle) {
+		t.Errorf("timestamp colour = %v, want the light theme's", timestampStyle.GetForeground())
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timerTickInterval is how often the running timer's clock is redrawn
+const timerTickInterval = time.Second
+
+// timerTickMsg redraws the running timer's clock
+type timerTickMsg struct{}
+
+// timerTick schedules the next clock redraw
+func timerTick() tea.Cmd {
+	return tea.Tick(timerTickInterval, func(time.Time) tea.Msg { return timerTickMsg{} })
+}
+
+// timerRunning reports whether the todo's last time session is still open
+func timerRunning(todo Todo) bool {
+	n := len(todo.TimeLog)
+	return n > 0 && todo.TimeLog[n-1].End == nil
+}
+
+// timeSpent returns the total time logged on the todo, counting a running session up
+// to now
+func timeSpent(todo Todo, now time.Time) time.Duration {
+	var total time.Duration
+	for _, session := range todo.TimeLog {
+		end := now
+		if session.End != nil {
+			end = *session.End
+		}
+		if end.After(session.Start) {
+			total += end.Sub(session.Start)
+		}
+	}
+	return total
+}
+
+// timeSpentOn returns the total time logged on todos
+func timeSpentOn(todos []Todo, now time.Time) time.Duration {
+	var total time.Duration
+	for _, todo := range todos {
+		total += timeSpent(todo, now)
+	}
+	return total
+}
+
+// weekTimeSpent returns the total time logged on the todos completed in week
+func weekTimeSpent(week WeekGroup, now time.Time) time.Duration {
+	var total time.Duration
+	for _, day := range week.Days {
+		total += timeSpentOn(day.Todos, now)
+	}
+	return total
+}
+
+// stopTimer closes the todo's running session at now, reporting whether one was running
+func stopTimer(todo *Todo, now time.Time) bool {
+	if !timerRunning(*todo) {
+		return false
+	}
+	todo.TimeLog[len(todo.TimeLog)-1].End = &now
+	return true
+}
+
+// formatDuration renders logged time for billing, e.g. "1h 05m" or "25m"
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// formatClock renders a running timer's elapsed time, e.g. "1:02:03"
+func formatClock(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// renderTimeSpent renders the ticking clock of a running timer, or the total time
+// logged on the todo, or "" if it has none
+func renderTimeSpent(todo Todo, now time.Time) string {
+	if timerRunning(todo) {
+		return timerStyle.Render("⏱ " + formatClock(timeSpent(todo, now)))
+	}
+	if spent := timeSpent(todo, now); spent > 0 {
+		return timestampStyle.Render("⏱ " + formatDuration(spent))
+	}
+	return ""
+}
+
+// runningTimer returns the todo whose timer is running, if any
+func (m *Model) runningTimer() (Todo, bool) {
+	for _, v := range columnOrder() {
+		todos := m.completed
+		if list := m.listFor(v); list != nil {
+			todos = *list
+		}
+		for _, todo := range todos {
+			if timerRunning(todo) {
+				return todo, true
+			}
+		}
+	}
+	return Todo{}, false
+}
+
+// stopRunningTimers stops every running timer at now, returning the views whose todos
+// changed
+func (m *Model) stopRunningTimers(now time.Time) []view {
+	var changed []view
+	for _, v := range columnOrder() {
+		todos := m.completed
+		if list := m.listFor(v); list != nil {
+			todos = *list
+		}
+		for i := range todos {
+			if stopTimer(&todos[i], now) {
+				changed = append(changed, v)
+			}
+		}
+	}
+	return changed
+}
+
+// timed reports whether todos in v can be timed. Timers run on todos being worked on,
+// so not in the backlog or the done column.
+func timed(v view) bool {
+	return v != viewBacklog && v != viewCompleted
+}
+
+// toggleTimer stops the selected todo's timer, or starts it after stopping any other
+// running timer so time is only logged on one todo at a time
+func (m *Model) toggleTimer() tea.Cmd {
+	if !timed(m.currentView) {
+		m.message = "Move the todo out of the " + viewName(m.currentView) + " to time it"
+		return nil
+	}
+	idx := m.selectedIndex()
+	if idx < 0 {
+		return nil
+	}
+
+	m.pushUndo()
+	now := time.Now()
+	list := m.listFor(m.currentView)
+	todo := &(*list)[idx]
+	if stopTimer(todo, now) {
+		if cmd := m.saveViews(m.currentView); cmd != nil {
+			return cmd
+		}
+		m.message = fmt.Sprintf("Timer stopped (%s logged in total)", formatDuration(timeSpent(*todo, now)))
+		return nil
+	}
+
+	changed := append(m.stopRunningTimers(now), m.currentView)
+	todo.TimeLog = append(todo.TimeLog, TimeSession{Start: now})
+	if cmd := m.saveViews(changed...); cmd != nil {
+		return cmd
+	}
+	m.message = "Timer started (m to stop)"
+	return m.startTimerTicks()
+}
+
+// startTimerTicks starts redrawing the clock every second unless it already is
+func (m *Model) startTimerTicks() tea.Cmd {
+	if m.timerTicking {
+		return nil
+	}
+	m.timerTicking = true
+	return timerTick()
+}
+
+// advanceTimer keeps the clock ticking while a timer runs
+func (m *Model) advanceTimer() tea.Cmd {
+	if _, ok := m.runningTimer(); !ok {
+		m.timerTicking = false
+		return nil
+	}
+	return timerTick()
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+		clock    string
+	}{
+		{0, "0m", "0:00:00"},
+		{25*time.Minute + 10*time.Second, "25m", "0:25:10"},
+		{65 * time.Minute, "1h 05m", "1:05:00"},
+		{10*time.Hour + 59*time.Minute + 45*time.Second, "11h 00m", "10:59:45"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.duration); got != tt.expected {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.duration, got, tt.expected)
+		}
+		if got := formatClock(tt.duration); got != tt.clock {
+			t.Errorf("formatClock(%v) = %q, want %q", tt.duration, got, tt.clock)
+		}
+	}
+}
+
+func TestTimeSpent(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+	todo := Todo{TimeLog: []TimeSession{
+		{Start: start, End: &end},
+		{Start: start.Add(time.Hour)},
+	}}
+
+	if !timerRunning(todo) {
+		t.Error("an open session should be running")
+	}
+	if got := timeSpent(todo, start.Add(90*time.Minute)); got != time.Hour {
+		t.Errorf("timeSpent() = %v, want 1h counting the running session", got)
+	}
+	if !stopTimer(&todo, start.Add(2*time.Hour)) || timerRunning(todo) {
+		t.Error("stopTimer() should close the running session")
+	}
+	if stopTimer(&todo, start.Add(3*time.Hour)) {
+		t.Error("stopTimer() should report no running timer")
+	}
+	if got := timeSpent(todo, start.Add(5*time.Hour)); got != 90*time.Minute {
+		t.Errorf("timeSpent() = %v, want 1h30m", got)
+	}
+}
+
+func TestUpdateTimer(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		backlog:     []Todo{{ID: "b", Text: "Someday"}},
+		ready:       []Todo{{ID: "1", Text: "Client A"}, {ID: "2", Text: "Client B"}},
+	}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		return cmd
+	}
+
+	// 'm' starts a timer and the clock ticks while it runs
+	if cmd := press("m"); cmd == nil || !timerRunning(m.ready[0]) {
+		t.Fatal("'m' should start the timer and its ticks")
+	}
+	if view := m.View(); !strings.Contains(view, "Timing:") || !strings.Contains(view, "⏱ 0:00:0") {
+		t.Errorf("View should show the running clock:\n%s", view)
+	}
+	updated, cmd := m.Update(timerTickMsg{})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Error("the clock should keep ticking while the timer runs")
+	}
+
+	// Starting another timer stops the first, without a second tick loop
+	press("j")
+	if cmd := press("m"); cmd != nil {
+		t.Error("the clock is already ticking")
+	}
+	if timerRunning(m.ready[0]) || !timerRunning(m.ready[1]) {
+		t.Fatalf("only the second todo's timer should run: %+v", m.ready)
+	}
+	saved := loadTodos(readyFile)
+	if len(saved[0].TimeLog) != 1 || saved[0].TimeLog[0].End == nil || !timerRunning(saved[1]) {
+		t.Errorf("saved = %+v, want the sessions saved", saved)
+	}
+
+	// Completing a todo stops its timer, and the ticks stop with it
+	press("x")
+	if len(m.completed) != 1 || timerRunning(m.completed[0]) {
+		t.Fatalf("completed = %+v, want the timer stopped", m.completed)
+	}
+	updated, cmd = m.Update(timerTickMsg{})
+	m = updated.(Model)
+	if cmd != nil || m.timerTicking {
+		t.Error("the clock should stop ticking once no timer runs")
+	}
+
+	// Moving a todo to the backlog stops its timer too
+	press("m")
+	press("b")
+	if len(m.backlog) != 2 || timerRunning(m.backlog[0]) {
+		t.Fatalf("backlog = %+v, want the moved todo's timer stopped", m.backlog)
+	}
+	if saved := loadTodos(backlogFile); len(saved) != 2 || timerRunning(saved[0]) {
+		t.Errorf("saved = %+v, want the stopped session saved", saved)
+	}
+
+	// Timers only run on todos being worked on
+	press("h")
+	press("m")
+	if timerRunning(m.backlog[0]) {
+		t.Error("backlog todos shouldn't be timed")
+	}
+}
+
+func TestTimeSpentInExport(t *testing.T) {
+	completedAt := time.Date(2026, 3, 3, 17, 0, 0, 0, time.Local)
+	session := func(minutes int) []TimeSession {
+		start := completedAt.Add(-4 * time.Hour)
+		end := start.Add(time.Duration(minutes) * time.Minute)
+		return []TimeSession{{Start: start, End: &end}}
+	}
+	todos := []Todo{
+		{Text: "Invoice A", CompletedAt: &completedAt, TimeLog: session(45)},
+		{Text: "Invoice B", CompletedAt: &completedAt, TimeLog: session(30)},
+	}
+
+	markdown := generateMarkdownFromTodos(todos, false)
+	for _, want := range []string{"**Invoice A** _5:00 PM_ (45m)", "(1h 15m logged)", "2 todos completed this week, 1h 15m logged"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown should contain %q:\n%s", want, markdown)
+		}
+	}
+
+	m := Model{currentView: viewCompleted, completed: todos, showingPrettify: true}
+	if view := m.View(); !strings.Contains(view, "2 todos, 1h 15m logged") {
+		t.Errorf("prettify view should sum the time logged:\n%s", view)
+	}
+}
@@ -20,19 +20,49 @@ const (
 )
 
 type Todo struct {
-	Text         string     `json:"text"`
-	CompleteNote string     `json:"complete_note,omitempty"`
-	Updates      []string   `json:"updates,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ID           string          `json:"id,omitempty"`
+	Text         string          `json:"text"`
+	CompleteNote string          `json:"complete_note,omitempty"`
+	Updates      []TodoUpdate    `json:"updates,omitempty"`
+	Checklist    []ChecklistItem `json:"checklist,omitempty"`
+	TimeLog      []TimeSession   `json:"time_log,omitempty"`
+	Tags         []string        `json:"tags,omitempty"`
+	Priority     string          `json:"priority,omitempty"`
+	Recurrence   string          `json:"recurrence,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	DueAt        *time.Time      `json:"due_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
 }
 
-// UnmarshalJSON provides backward compatibility for loading old single-string descriptions.
+// TodoUpdate is a progress note on a todo, most recent first in Todo.Updates.
+// Updates saved before they were timestamped have a zero CreatedAt.
+type TodoUpdate struct {
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at,omitzero"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	Author    string     `json:"author,omitempty"`
+}
+
+// TimeSession is a stretch of time spent on a todo. End is nil while its timer runs.
+type TimeSession struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// ChecklistItem is one step of a todo's checklist
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// UnmarshalJSON provides backward compatibility for loading old single-string descriptions,
+// updates saved as plain strings and todos saved before tags existed.
 func (t *Todo) UnmarshalJSON(data []byte) error {
 	type Alias Todo
 	aux := &struct {
 		Updates     interface{} `json:"updates,omitempty"`
 		Description interface{} `json:"description,omitempty"`
+		Tags        interface{} `json:"tags,omitempty"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -42,26 +72,36 @@ func (t *Todo) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	parseUpdates := func(raw interface{}) []string {
-		var updates []string
+	parseUpdates := func(raw interface{}) []TodoUpdate {
+		var updates []TodoUpdate
 		switch value := raw.(type) {
 		case string:
 			if value != "" {
-				updates = []string{value}
+				updates = []TodoUpdate{{Text: value}}
 			}
 		case []interface{}:
 			for _, v := range value {
-				if s, ok := v.(string); ok {
-					updates = append(updates, s)
+				switch item := v.(type) {
+				case string:
+					updates = append(updates, TodoUpdate{Text: item})
+				case map[string]interface{}:
+					// Decode structured updates again now that we know their shape
+					data, err := json.Marshal(item)
+					if err != nil {
+						continue
+					}
+					var update TodoUpdate
+					if err := json.Unmarshal(data, &update); err == nil {
+						updates = append(updates, update)
+					}
 				}
 			}
-		case []string:
-			updates = append(updates, value...)
 		}
 		return updates
 	}
 
-	// Handle updates field - can be string (old format) or []string (new format).
+	// Handle updates field - a string (oldest format), a list of strings (old format) or
+	// a list of update records.
 	if aux.Updates != nil {
 		t.Updates = parseUpdates(aux.Updates)
 	} else if aux.Description != nil {
@@ -69,14 +109,44 @@ func (t *Todo) UnmarshalJSON(data []byte) error {
 		t.Updates = parseUpdates(aux.Description)
 	}
 
+	// Tags can be a list or a comma-separated string; older todos take them from #hashtags.
+	switch value := aux.Tags.(type) {
+	case string:
+		t.Tags = parseTagInput(value)
+	case []interface{}:
+		t.Tags = nil
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				t.Tags = mergeTags(t.Tags, parseTagInput(s))
+			}
+		}
+	default:
+		t.Tags = parseTags(t.Text)
+	}
+
 	return nil
 }
 
+// searchOrigin records where the cursor was when a search started
+type searchOrigin struct {
+	view   view
+	cursor int
+}
+
 type Model struct {
 	backlog                []Todo
 	ready                  []Todo
 	completed              []Todo
-	displayedCompleted     []Todo // Stores the filtered/sorted completed todos for display
+	stages                 [][]Todo // Todos of the columns between ready and the done column (see columns)
+	displayedCompleted     []Todo   // Stores the filtered/sorted completed todos for display
+	completedPage          int      // Which page of the completed history is shown, 0 being the most recent
+	completedTotal         int      // Completed todos in the history pages, counting loaded backups
+	archive                []Todo   // Todos from backup files, loaded when paging past the Completed file
+	archiveLoaded          bool     // True once archive has been read
+	archivedTotal          int      // Todos in the backup files, going by their names
+	archivedCounted        bool     // True once archivedTotal is counted; reset when a backup file changes
+	jumpingToDate          bool     // True when typing a date to jump to in the completed history
+	newJumpDate            string   // Buffer for the jump to date prompt
 	cursor                 int
 	currentView            view
 	adding                 bool
@@ -92,13 +162,58 @@ type Model struct {
 	showingAllUpdates      bool
 	showingCommands        bool
 	confirmingDelete       bool
-	navigatingUpdates      bool // True when in update navigation mode
-	updateCursor           int  // Which update is selected (0-indexed)
-	confirmingDeleteUpdate bool // True when confirming update deletion
-	showingPrettify        bool // True when in prettify view (Completed tab only)
+	confirmingMerge        bool            // True when asking whether to merge conflicted copies found at startup
+	syncConflicts          []syncConflict  // Conflicted copies found at startup
+	navigatingUpdates      bool            // True when in update navigation mode
+	updateCursor           int             // Which update is selected (0-indexed)
+	confirmingDeleteUpdate bool            // True when confirming update deletion
+	showingPrettify        bool            // True when in prettify view (Completed tab only)
+	showingStats           bool            // True when in the stats view
+	stats                  completionStats // Stats shown in the stats view, computed when it opens
+	browsingArchives       bool            // True in the archive view, listing the backup files made with B
+	archiveFiles           []archiveFile   // Backup files listed in the archive view, newest first
+	archiveCursor          int             // Which archive is selected, and open while readingArchive
+	readingArchive         bool            // True when the selected archive is open, showing its todos
+	archiveTodoCursor      int             // Which todo of the open archive is selected
+	searchingArchive       bool            // True while typing a search of the open archive
+	archiveQuery           string          // Only the open archive's todos matching this are shown
 	saveError              string
 	message                string
-	textInputCursor        int // Cursor position within text input fields (for arrow key navigation)
-	width                  int // Terminal width
-	height                 int // Terminal height
+	editingTags            bool                 // True when editing the selected todo's tags
+	newTags                string               // Buffer for tag editing
+	filteringTags          bool                 // True when typing a tag filter
+	newTagFilter           string               // Buffer for the tag filter prompt
+	tagFilter              []string             // Only todos with one of these tags are shown, when set
+	editingDue             bool                 // True when editing the selected todo's due date
+	newDue                 string               // Buffer for due date editing
+	sortMode               sortMode             // Display order of backlog and ready
+	editingRecurrence      bool                 // True when editing the selected todo's recurrence rule
+	newRecurrence          string               // Buffer for recurrence editing
+	searching              bool                 // True while typing a search query
+	searchActive           bool                 // True after confirming a search; n/N jump between matches
+	searchQuery            string               // Current search query
+	searchAll              bool                 // Also search updates and complete notes
+	searchMatches          []searchMatch        // Matches for searchQuery across all lists and archives
+	searchIndex            int                  // Which match is selected
+	searchOrigin           searchOrigin         // View and cursor to return to when a search is cancelled
+	searchArchives         []archivedTodo       // Todos from backup files, loaded when a search starts
+	undoStack              []snapshot           // States before each mutating action, most recent last
+	redoStack              []snapshot           // States undone with ctrl+z, most recent last
+	textInputCursor        int                  // Cursor position within text input fields (for arrow key navigation)
+	showingBoard           bool                 // Show every column side by side when the terminal is wide enough
+	navigatingChecklist    bool                 // True when moving through the selected todo's checklist
+	timerTicking           bool                 // True while the running timer's clock is being redrawn every second
+	checklistCursor        int                  // Which checklist item is selected
+	editingChecklist       bool                 // True when typing a checklist item
+	renamingChecklistItem  bool                 // True when the checklist prompt edits the selected item rather than adding one
+	newChecklistItem       string               // Buffer for checklist item editing
+	animation              boardAnimation       // Todo sliding across the board after a move
+	files                  map[string]fileState // What each column's file held when last loaded or saved
+	readOnly               bool                 // True when another copy of the app holds the lock, so nothing is saved
+	holdsLock              bool                 // True when this copy holds the lock on the todo files
+	lockOwner              lockOwner            // Who holds the lock when read-only
+	lockRefreshed          time.Time            // When the lock was last touched
+	fileWarning            string               // Shown until the next key press after another program's changes are merged
+	width                  int                  // Terminal width
+	height                 int                  // Terminal height
 }
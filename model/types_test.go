@@ -110,8 +110,8 @@ func TestTodoUnmarshalJSON(t *testing.T) {
 						t.Errorf("Missing update at index %d", i)
 						continue
 					}
-					if todo.Updates[i] != expectedUpdate {
-						t.Errorf("Updates[%d] = %q, want %q", i, todo.Updates[i], expectedUpdate)
+					if todo.Updates[i].Text != expectedUpdate {
+						t.Errorf("Updates[%d] = %q, want %q", i, todo.Updates[i].Text, expectedUpdate)
 					}
 				}
 
@@ -161,7 +161,7 @@ func TestTodoMarshalJSON(t *testing.T) {
 			name: "todo with updates array",
 			todo: Todo{
 				Text:      "Task 1",
-				Updates:   []string{"desc1", "desc2"},
+				Updates:   textUpdates("desc1", "desc2"),
 				CreatedAt: now,
 			},
 			checkFields: []string{`"text":"Task 1"`, `"updates"`, `"created_at"`},
@@ -232,8 +232,8 @@ func TestTodoBackwardCompatibility(t *testing.T) {
 	if len(todo.Updates) != 1 {
 		t.Errorf("Updates should be converted to array with 1 element, got %d", len(todo.Updates))
 	}
-	if len(todo.Updates) > 0 && todo.Updates[0] != "single string" {
-		t.Errorf("Updates[0] = %q, want 'single string'", todo.Updates[0])
+	if len(todo.Updates) > 0 && todo.Updates[0].Text != "single string" {
+		t.Errorf("Updates[0].Text = %q, want 'single string'", todo.Updates[0].Text)
 	}
 
 	// Marshal back to JSON (should use new format)
@@ -243,8 +243,59 @@ func TestTodoBackwardCompatibility(t *testing.T) {
 	}
 
 	jsonStr := string(data)
-	// New format should have array
-	if !contains(jsonStr, `"updates":["single string"]`) {
+	// New format should have a list of update records
+	if !contains(jsonStr, `"updates":[{"text":"single string"}]`) {
 		t.Errorf("Marshaled JSON should use array format, got: %s", jsonStr)
 	}
 }
+
+func TestTodoUnmarshalJSONTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonData string
+		expected []string
+		update   string
+	}{
+		{
+			name:     "tags array",
+			jsonData: `{"text":"Task","tags":["infra","ops"],"created_at":"2024-01-01T10:00:00Z"}`,
+			expected: []string{"infra", "ops"},
+			update:   "Tags are stored as an array",
+		},
+		{
+			name:     "tags string",
+			jsonData: `{"text":"Task","tags":"Infra, ops","created_at":"2024-01-01T10:00:00Z"}`,
+			expected: []string{"infra", "ops"},
+			update:   "A comma-separated string is accepted",
+		},
+		{
+			name:     "legacy hashtags",
+			jsonData: `{"text":"Deploy #infra","created_at":"2024-01-01T10:00:00Z"}`,
+			expected: []string{"infra"},
+			update:   "Todos saved before tags existed take them from the text",
+		},
+		{
+			name:     "no tags",
+			jsonData: `{"text":"Task","created_at":"2024-01-01T10:00:00Z"}`,
+			expected: nil,
+			update:   "Todos without tags stay untagged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var todo Todo
+			if err := json.Unmarshal([]byte(tt.jsonData), &todo); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(todo.Tags) != len(tt.expected) {
+				t.Fatalf("Tags = %v, want %v (%s)", todo.Tags, tt.expected, tt.update)
+			}
+			for i := range tt.expected {
+				if todo.Tags[i] != tt.expected[i] {
+					t.Errorf("Tags[%d] = %q, want %q", i, todo.Tags[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
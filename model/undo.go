@@ -0,0 +1,204 @@
+package model
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndoHistory limits how many actions can be undone
+const maxUndoHistory = 100
+
+// snapshot records the lists and cursor position at a point in time
+type snapshot struct {
+	backlog     []Todo
+	ready       []Todo
+	completed   []Todo
+	stages      [][]Todo
+	cursor      int
+	currentView view
+	// files are the files besides the column files that the action following this
+	// snapshot wrote or removed, such as the archive written by 'B'
+	files []fileChange
+}
+
+// fileChange is a file written or removed by an action, holding its todos before and
+// after so undo and redo can put it back. A nil before or after means the file didn't
+// exist.
+type fileChange struct {
+	name          string
+	before, after []Todo
+}
+
+// cloneTodos returns a deep copy of todos so later in-place edits don't leak into history
+func cloneTodos(todos []Todo) []Todo {
+	if todos == nil {
+		return nil
+	}
+	cloned := make([]Todo, len(todos))
+	for i, todo := range todos {
+		if todo.Updates != nil {
+			todo.Updates = append([]TodoUpdate(nil), todo.Updates...)
+		}
+		if todo.TimeLog != nil {
+			todo.TimeLog = append([]TimeSession(nil), todo.TimeLog...)
+		}
+		if todo.Checklist != nil {
+			todo.Checklist = append([]ChecklistItem(nil), todo.Checklist...)
+		}
+		if todo.Tags != nil {
+			todo.Tags = append([]string(nil), todo.Tags...)
+		}
+		if todo.DueAt != nil {
+			dueAt := *todo.DueAt
+			todo.DueAt = &dueAt
+		}
+		if todo.CompletedAt != nil {
+			completedAt := *todo.CompletedAt
+			todo.CompletedAt = &completedAt
+		}
+		cloned[i] = todo
+	}
+	return cloned
+}
+
+// cloneStages returns a deep copy of the stage columns' todos
+func cloneStages(stages [][]Todo) [][]Todo {
+	if stages == nil {
+		return nil
+	}
+	cloned := make([][]Todo, len(stages))
+	for i, todos := range stages {
+		cloned[i] = cloneTodos(todos)
+	}
+	return cloned
+}
+
+// takeSnapshot captures the current lists and cursor
+func (m *Model) takeSnapshot() snapshot {
+	return snapshot{
+		backlog:     cloneTodos(m.backlog),
+		ready:       cloneTodos(m.ready),
+		completed:   cloneTodos(m.completed),
+		stages:      cloneStages(m.stages),
+		cursor:      m.cursor,
+		currentView: m.currentView,
+	}
+}
+
+// pushUndo records the current state before a mutating action and clears redo history
+func (m *Model) pushUndo() {
+	m.undoStack = append(m.undoStack, m.takeSnapshot())
+	if len(m.undoStack) > maxUndoHistory {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndoHistory:]
+	}
+	m.redoStack = nil
+}
+
+// recordFileChange notes on the most recent undo entry that filename went from holding
+// before to holding after, with nil for a file that didn't exist or was removed
+func (m *Model) recordFileChange(filename string, before, after []Todo) {
+	if len(m.undoStack) == 0 {
+		return
+	}
+	last := &m.undoStack[len(m.undoStack)-1]
+	last.files = append(last.files, fileChange{filename, cloneTodos(before), cloneTodos(after)})
+}
+
+// writeOrRemove saves todos to filename, or removes it when todos is nil
+func writeOrRemove(filename string, todos []Todo) error {
+	if todos == nil {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return saveTodos(filename, todos)
+}
+
+// revertFileChanges puts the files in changes back as they were, undoing the last
+// change first
+func revertFileChanges(changes []fileChange) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		writeOrRemove(changes[i].name, changes[i].before)
+	}
+}
+
+// undo restores the state before the most recent action
+func (m *Model) undo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		m.message = "Nothing to undo"
+		return nil
+	}
+	prev := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	current := m.takeSnapshot()
+	current.files = prev.files
+	m.redoStack = append(m.redoStack, current)
+
+	for i := len(prev.files) - 1; i >= 0; i-- {
+		if err := writeOrRemove(prev.files[i].name, prev.files[i].before); err != nil {
+			m.message = "Undo failed: " + err.Error()
+			return nil
+		}
+	}
+
+	if cmd := m.restoreSnapshot(prev); cmd != nil {
+		return cmd
+	}
+	m.message = "Undone"
+	return nil
+}
+
+// redo reapplies the most recently undone action
+func (m *Model) redo() tea.Cmd {
+	if len(m.redoStack) == 0 {
+		m.message = "Nothing to redo"
+		return nil
+	}
+	next := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+
+	current := m.takeSnapshot()
+	current.files = next.files
+	m.undoStack = append(m.undoStack, current)
+
+	for _, change := range next.files {
+		if err := writeOrRemove(change.name, change.after); err != nil {
+			m.message = "Redo failed: " + err.Error()
+			return nil
+		}
+	}
+
+	if cmd := m.restoreSnapshot(next); cmd != nil {
+		return cmd
+	}
+	m.message = "Redone"
+	return nil
+}
+
+// restoreSnapshot replaces the lists and cursor with s and saves every column's file
+func (m *Model) restoreSnapshot(s snapshot) tea.Cmd {
+	m.backlog = cloneTodos(s.backlog)
+	m.ready = cloneTodos(s.ready)
+	m.completed = cloneTodos(s.completed)
+	m.stages = cloneStages(s.stages)
+	m.currentView = s.currentView
+	m.cursor = s.cursor
+	m.forgetArchive()
+	m.updateDisplayedCompleted()
+
+	if list := m.getCurrentList(); m.cursor >= len(list) {
+		m.cursor = len(list) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.showingUpdate = false
+	m.navigatingUpdates = false
+	m.updateCursor = 0
+	m.showingPrettify = false
+
+	return m.saveViews(columnOrder()...)
+}
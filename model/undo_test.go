@@ -0,0 +1,252 @@
+package model
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCloneTodos(t *testing.T) {
+	now := time.Now()
+	original := []Todo{
+		{ID: "a", Text: "task", Updates: textUpdates("u1"), CreatedAt: now, CompletedAt: &now},
+	}
+
+	cloned := cloneTodos(original)
+	cloned[0].Text = "changed"
+	cloned[0].Updates[0].Text = "changed"
+	*cloned[0].CompletedAt = now.Add(time.Hour)
+
+	if original[0].Text != "task" {
+		t.Error("cloneTodos() should copy todo fields")
+	}
+	if original[0].Updates[0].Text != "u1" {
+		t.Error("cloneTodos() should copy the Updates slice")
+	}
+	if !original[0].CompletedAt.Equal(now) {
+		t.Error("cloneTodos() should copy CompletedAt")
+	}
+
+	if cloneTodos(nil) != nil {
+		t.Error("cloneTodos(nil) should return nil")
+	}
+}
+
+func TestUndoRedoDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog: []Todo{
+			{ID: "1", Text: "task1", CreatedAt: time.Now()},
+			{ID: "2", Text: "task2", CreatedAt: time.Now()},
+		},
+		cursor: 1,
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if len(m.backlog) != 1 {
+		t.Fatalf("backlog len after delete = %d, want 1", len(m.backlog))
+	}
+
+	// Undo restores the todo, the cursor and the file
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if len(m.backlog) != 2 || m.backlog[1].ID != "2" {
+		t.Fatalf("backlog after undo = %v, want both todos", m.backlog)
+	}
+	if m.cursor != 1 {
+		t.Errorf("cursor after undo = %d, want 1", m.cursor)
+	}
+	if saved := loadTodos(backlogFile); len(saved) != 2 {
+		t.Errorf("saved backlog len after undo = %d, want 2", len(saved))
+	}
+
+	// Redo deletes it again
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	if len(m.backlog) != 1 || m.backlog[0].ID != "1" {
+		t.Errorf("backlog after redo = %v, want only task1", m.backlog)
+	}
+	if saved := loadTodos(backlogFile); len(saved) != 1 {
+		t.Errorf("saved backlog len after redo = %d, want 1", len(saved))
+	}
+}
+
+func TestUndoMultipleLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "task1", CreatedAt: time.Now()},
+			{ID: "2", Text: "task2", CreatedAt: time.Now()},
+			{ID: "3", Text: "task3", CreatedAt: time.Now()},
+		},
+	}
+
+	// Reorder, then complete
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.ready) != 2 || len(m.completed) != 1 {
+		t.Fatalf("ready/completed = %d/%d, want 2/1", len(m.ready), len(m.completed))
+	}
+
+	// 'U' is an alias for undo
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	m = updated.(Model)
+	if len(m.ready) != 3 || len(m.completed) != 0 {
+		t.Fatalf("ready/completed after first undo = %d/%d, want 3/0", len(m.ready), len(m.completed))
+	}
+	if m.ready[1].ID != "1" {
+		t.Errorf("ready[1].ID after first undo = %q, want reordered state", m.ready[1].ID)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if m.ready[0].ID != "1" || m.ready[1].ID != "2" {
+		t.Errorf("ready after second undo = %v, want original order", m.ready)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if m.message != "Nothing to undo" {
+		t.Errorf("message = %q, want 'Nothing to undo'", m.message)
+	}
+}
+
+func TestUndoEditIsolatedFromLaterChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView:  viewReady,
+		ready:        []Todo{{ID: "1", Text: "Original", CreatedAt: time.Now()}},
+		renamingTodo: true,
+		newTodoName:  "Renamed",
+	}
+
+	// Renaming edits the todo in place; the snapshot must not see the change
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.ready[0].Text != "Renamed" {
+		t.Fatalf("Text after rename = %q, want 'Renamed'", m.ready[0].Text)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if m.ready[0].Text != "Original" {
+		t.Errorf("Text after undo = %q, want 'Original'", m.ready[0].Text)
+	}
+}
+
+func TestUndoBackupAndClear(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	now := time.Now()
+	m := Model{
+		currentView: viewCompleted,
+		completed: []Todo{
+			{ID: "1", Text: "done1", CreatedAt: now, CompletedAt: &now},
+			{ID: "2", Text: "done2", CreatedAt: now, CompletedAt: &now},
+		},
+	}
+	m.updateDisplayedCompleted()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'B'}})
+	m = updated.(Model)
+	if len(m.completed) != 0 {
+		t.Fatalf("completed len after B = %d, want 0", len(m.completed))
+	}
+	backups, _ := findBackupFiles()
+	if len(backups) != 1 {
+		t.Fatalf("backup files after B = %v, want 1", backups)
+	}
+
+	// Undo restores the completed list and removes the archive
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if len(m.completed) != 2 || len(m.displayedCompleted) != 2 {
+		t.Errorf("completed/displayed after undo = %d/%d, want 2/2", len(m.completed), len(m.displayedCompleted))
+	}
+	if backups, _ := findBackupFiles(); len(backups) != 0 {
+		t.Errorf("backup files after undo = %v, want none", backups)
+	}
+
+	// Redo clears the list and writes the archive again
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	if len(m.completed) != 0 {
+		t.Errorf("completed len after redo = %d, want 0", len(m.completed))
+	}
+	backups, _ = findBackupFiles()
+	if len(backups) != 1 {
+		t.Fatalf("backup files after redo = %v, want 1", backups)
+	}
+	if archived := loadTodos(backups[0]); len(archived) != 2 {
+		t.Errorf("archived todos after redo = %d, want 2", len(archived))
+	}
+}
+
+func TestNewActionClearsRedo(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{
+		currentView: viewReady,
+		ready: []Todo{
+			{ID: "1", Text: "task1", CreatedAt: time.Now()},
+			{ID: "2", Text: "task2", CreatedAt: time.Now()},
+		},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'J'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = updated.(Model)
+	if len(m.redoStack) != 1 {
+		t.Fatalf("redoStack len = %d, want 1", len(m.redoStack))
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = updated.(Model)
+	if len(m.redoStack) != 0 {
+		t.Errorf("redoStack len after new action = %d, want 0", len(m.redoStack))
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = updated.(Model)
+	if m.message != "Nothing to redo" {
+		t.Errorf("message = %q, want 'Nothing to redo'", m.message)
+	}
+}
+
+func TestUndoHistoryLimit(t *testing.T) {
+	m := Model{}
+	for i := 0; i < maxUndoHistory+10; i++ {
+		m.pushUndo()
+	}
+	if len(m.undoStack) != maxUndoHistory {
+		t.Errorf("undoStack len = %d, want %d", len(m.undoStack), maxUndoHistory)
+	}
+}
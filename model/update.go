@@ -15,35 +15,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	case boardAnimationMsg:
+		return m, m.advanceBoardAnimation()
+	case timerTickMsg:
+		return m, m.advanceTimer()
+	case fileCheckMsg:
+		m.checkLock(time.Now())
+		if cmd := m.reloadChangedFiles(); cmd != nil {
+			return m, cmd
+		}
+		return m, watchFiles()
 	case tea.KeyMsg:
+		m.fileWarning = ""
 		if m.adding {
 			switch msg.String() {
 			case "enter":
 				if strings.TrimSpace(m.newTodo) != "" {
+					m.pushUndo()
 					newTodo := Todo{
-						Text:      capitalizeFirst(m.newTodo),
+						ID:        newTodoID(),
+						Text:      formatTodoText(m.newTodo),
+						Tags:      parseTags(m.newTodo),
 						CreatedAt: time.Now(),
 					}
-					if m.currentView == viewBacklog {
-						if m.addingToTop {
-							m.backlog = append([]Todo{newTodo}, m.backlog...)
-							m.cursor = 0
-						} else {
-							m.backlog = append(m.backlog, newTodo)
-						}
-						if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-							return m, cmd
-						}
+					list := m.listFor(m.currentView)
+					if m.addingToTop {
+						*list = append([]Todo{newTodo}, *list...)
+						m.cursor = 0
 					} else {
-						if m.addingToTop {
-							m.ready = append([]Todo{newTodo}, m.ready...)
-							m.cursor = 0
-						} else {
-							m.ready = append(m.ready, newTodo)
-						}
-						if cmd := m.save(readyFile, m.ready); cmd != nil {
-							return m, cmd
-						}
+						*list = append(*list, newTodo)
+					}
+					if cmd := m.save(columnOf(m.currentView).file, *list); cmd != nil {
+						return m, cmd
 					}
 					m.message = "Todo added!"
 				}
@@ -61,6 +64,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.searching {
+			switch msg.String() {
+			case "enter":
+				m.searching = false
+				if len(m.searchMatches) == 0 {
+					m.message = fmt.Sprintf("No matches for %q", m.searchQuery)
+					m.clearSearch()
+				} else {
+					m.searchActive = true
+					m.jumpToMatch()
+					m.message += " (n/N: next/previous, esc: clear search)"
+				}
+			case "esc":
+				m.currentView = m.searchOrigin.view
+				m.updateDisplayedCompleted()
+				m.cursor = m.searchOrigin.cursor
+				m.clearSearch()
+				m.message = "Search cancelled"
+			case "tab":
+				// Toggle searching updates and complete notes as well as todo text
+				m.searchAll = !m.searchAll
+				m.updateSearchMatches()
+				m.jumpToFirstMatch()
+			default:
+				handleTextInput(msg.String(), &m.searchQuery, &m.textInputCursor)
+				m.updateSearchMatches()
+				m.jumpToFirstMatch()
+			}
+			return m, nil
+		}
+
 		if m.editingUpdate {
 			switch msg.String() {
 			case "enter":
@@ -68,42 +102,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(currentList) > 0 && m.cursor < len(currentList) {
 					trimmedUpdate := strings.TrimSpace(m.newUpdate)
 					if trimmedUpdate != "" {
+						m.pushUndo()
+						idx := m.selectedIndex()
+						now := time.Now()
 						switch m.currentView {
-						case viewBacklog:
-							if m.navigatingUpdates && m.updateCursor < len(m.backlog[m.cursor].Updates) {
-								// Update existing update
-								m.backlog[m.cursor].Updates[m.updateCursor] = trimmedUpdate
-							} else {
-								// Prepend new update
-								m.backlog[m.cursor].Updates = append([]string{trimmedUpdate}, m.backlog[m.cursor].Updates...)
-							}
-							if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-								return m, cmd
-							}
-						case viewReady:
-							if m.navigatingUpdates && m.updateCursor < len(m.ready[m.cursor].Updates) {
-								// Update existing update
-								m.ready[m.cursor].Updates[m.updateCursor] = trimmedUpdate
-							} else {
-								// Prepend new update
-								m.ready[m.cursor].Updates = append([]string{trimmedUpdate}, m.ready[m.cursor].Updates...)
-							}
-							if cmd := m.save(readyFile, m.ready); cmd != nil {
-								return m, cmd
-							}
 						case viewCompleted:
 							m.updateCompletedTodo(func(t *Todo) {
 								if m.navigatingUpdates && m.updateCursor < len(t.Updates) {
 									// Update existing update
-									t.Updates[m.updateCursor] = trimmedUpdate
+									editTodoUpdate(&t.Updates[m.updateCursor], trimmedUpdate, now)
 								} else {
 									// Prepend new update
-									t.Updates = append([]string{trimmedUpdate}, t.Updates...)
+									t.Updates = append([]TodoUpdate{newTodoUpdate(trimmedUpdate, now)}, t.Updates...)
 								}
 							})
 							if cmd := m.save(completedFile, m.completed); cmd != nil {
 								return m, cmd
 							}
+						default:
+							list := *m.listFor(m.currentView)
+							if m.navigatingUpdates && m.updateCursor < len(list[idx].Updates) {
+								// Update existing update
+								editTodoUpdate(&list[idx].Updates[m.updateCursor], trimmedUpdate, now)
+							} else {
+								// Prepend new update
+								list[idx].Updates = append([]TodoUpdate{newTodoUpdate(trimmedUpdate, now)}, list[idx].Updates...)
+							}
+							if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+								return m, cmd
+							}
 						}
 						m.message = "Update saved!"
 						m.showingUpdate = true
@@ -121,23 +148,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.editingChecklist {
+			return m, m.handleChecklistInput(msg.String())
+		}
+
 		if m.editingCompleteNote {
 			switch msg.String() {
 			case "enter":
 				currentList := m.getCurrentList()
 				if len(currentList) > 0 && m.cursor < len(currentList) {
 					trimmedNote := strings.TrimSpace(m.newCompleteNote)
+					m.pushUndo()
+					idx := m.selectedIndex()
 					switch m.currentView {
-					case viewBacklog:
-						m.backlog[m.cursor].CompleteNote = trimmedNote
-						if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-							return m, cmd
-						}
-					case viewReady:
-						m.ready[m.cursor].CompleteNote = trimmedNote
-						if cmd := m.save(readyFile, m.ready); cmd != nil {
-							return m, cmd
-						}
 					case viewCompleted:
 						m.updateCompletedTodo(func(t *Todo) {
 							t.CompleteNote = trimmedNote
@@ -145,6 +168,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if cmd := m.save(completedFile, m.completed); cmd != nil {
 							return m, cmd
 						}
+					default:
+						list := *m.listFor(m.currentView)
+						list[idx].CompleteNote = trimmedNote
+						if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+							return m, cmd
+						}
 					}
 					if trimmedNote == "" {
 						m.message = "Complete note removed"
@@ -171,25 +200,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if strings.TrimSpace(m.newTodoName) != "" {
 					currentList := m.getCurrentList()
 					if len(currentList) > 0 && m.cursor < len(currentList) {
-						capitalizedName := capitalizeFirst(m.newTodoName)
+						capitalizedName := formatTodoText(m.newTodoName)
+						m.pushUndo()
+						idx := m.selectedIndex()
 						switch m.currentView {
-						case viewBacklog:
-							m.backlog[m.cursor].Text = capitalizedName
-							if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-								return m, cmd
-							}
-						case viewReady:
-							m.ready[m.cursor].Text = capitalizedName
-							if cmd := m.save(readyFile, m.ready); cmd != nil {
-								return m, cmd
-							}
 						case viewCompleted:
 							m.updateCompletedTodo(func(t *Todo) {
 								t.Text = capitalizedName
+								t.Tags = mergeTags(t.Tags, parseTags(capitalizedName))
 							})
 							if cmd := m.save(completedFile, m.completed); cmd != nil {
 								return m, cmd
 							}
+						default:
+							list := *m.listFor(m.currentView)
+							list[idx].Text = capitalizedName
+							list[idx].Tags = mergeTags(list[idx].Tags, parseTags(capitalizedName))
+							if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+								return m, cmd
+							}
 						}
 						m.message = "Todo renamed!"
 					}
@@ -206,26 +235,202 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Handle update deletion confirmation (check this BEFORE navigatingUpdates)
-		if m.confirmingDeleteUpdate {
+		if m.editingTags {
 			switch msg.String() {
-			case "y":
-				// Delete the update
+			case "enter":
 				currentList := m.getCurrentList()
 				if len(currentList) > 0 && m.cursor < len(currentList) {
+					tags := parseTagInput(m.newTags)
+					m.pushUndo()
+					idx := m.selectedIndex()
 					switch m.currentView {
-					case viewBacklog:
-						updates := m.backlog[m.cursor].Updates
-						m.backlog[m.cursor].Updates = append(updates[:m.updateCursor], updates[m.updateCursor+1:]...)
-						if cmd := m.save(backlogFile, m.backlog); cmd != nil {
+					case viewCompleted:
+						m.updateCompletedTodo(func(t *Todo) {
+							t.Tags = tags
+						})
+						if cmd := m.save(completedFile, m.completed); cmd != nil {
 							return m, cmd
 						}
-					case viewReady:
-						updates := m.ready[m.cursor].Updates
-						m.ready[m.cursor].Updates = append(updates[:m.updateCursor], updates[m.updateCursor+1:]...)
-						if cmd := m.save(readyFile, m.ready); cmd != nil {
+					default:
+						list := *m.listFor(m.currentView)
+						list[idx].Tags = tags
+						if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
 							return m, cmd
 						}
+					}
+					if len(tags) == 0 {
+						m.message = "Tags removed"
+					} else {
+						m.message = "Tags updated: " + formatTags(tags)
+					}
+					// The todo may no longer match the filter
+					m.clampCursor()
+				}
+				m.editingTags = false
+				m.newTags = ""
+			case "esc":
+				m.editingTags = false
+				m.newTags = ""
+				m.message = "Cancelled"
+			default:
+				handleTextInput(msg.String(), &m.newTags, &m.textInputCursor)
+			}
+			return m, nil
+		}
+
+		if m.filteringTags {
+			switch msg.String() {
+			case "enter":
+				m.setTagFilter(parseTagInput(m.newTagFilter))
+				if len(m.tagFilter) == 0 {
+					m.message = "Filter cleared"
+				} else {
+					m.message = "Filtering by " + formatTags(m.tagFilter)
+				}
+				m.filteringTags = false
+				m.newTagFilter = ""
+			case "esc":
+				m.filteringTags = false
+				m.newTagFilter = ""
+				m.message = "Cancelled"
+			default:
+				handleTextInput(msg.String(), &m.newTagFilter, &m.textInputCursor)
+			}
+			return m, nil
+		}
+
+		if m.editingDue {
+			switch msg.String() {
+			case "enter":
+				if idx := m.selectedIndex(); idx >= 0 {
+					var dueAt *time.Time
+					if input := strings.TrimSpace(m.newDue); input != "" {
+						due, err := parseDueDate(input, time.Now())
+						if err != nil {
+							// Keep the prompt open so the date can be corrected
+							m.message = "Error: " + err.Error()
+							return m, nil
+						}
+						dueAt = &due
+					}
+					m.pushUndo()
+					list := *m.listFor(m.currentView)
+					list[idx].DueAt = dueAt
+					if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+						return m, cmd
+					}
+					if dueAt == nil {
+						m.message = "Due date removed"
+					} else {
+						m.message = "Due date updated: " + dueAt.Format("Mon Jan 2, 2006")
+					}
+				}
+				m.editingDue = false
+				m.newDue = ""
+			case "esc":
+				m.editingDue = false
+				m.newDue = ""
+				m.message = "Cancelled"
+			default:
+				handleTextInput(msg.String(), &m.newDue, &m.textInputCursor)
+			}
+			return m, nil
+		}
+
+		if m.jumpingToDate {
+			switch msg.String() {
+			case "enter":
+				m.jumpingToDate = false
+				m.jumpToDate(m.newJumpDate)
+				m.newJumpDate = ""
+			case "esc":
+				m.jumpingToDate = false
+				m.newJumpDate = ""
+				m.message = "Cancelled"
+			default:
+				handleTextInput(msg.String(), &m.newJumpDate, &m.textInputCursor)
+			}
+			return m, nil
+		}
+
+		if m.searchingArchive {
+			switch msg.String() {
+			case "enter":
+				m.searchingArchive = false
+				if matches := len(m.archiveTodos()); matches == 0 {
+					m.message = fmt.Sprintf("No matches for %q", m.archiveQuery)
+					m.archiveQuery = ""
+				} else {
+					m.message = fmt.Sprintf("%s matching (esc to clear the search)", pluralTodos(matches))
+				}
+			case "esc":
+				m.searchingArchive = false
+				m.archiveQuery = ""
+				m.archiveTodoCursor = 0
+				m.message = "Search cancelled"
+			default:
+				handleTextInput(msg.String(), &m.archiveQuery, &m.textInputCursor)
+				m.archiveTodoCursor = 0
+			}
+			return m, nil
+		}
+
+		if m.editingRecurrence {
+			switch msg.String() {
+			case "enter":
+				if idx := m.selectedIndex(); idx >= 0 {
+					now := time.Now()
+					var rule string
+					var firstDue *time.Time
+					if input := strings.TrimSpace(m.newRecurrence); input != "" {
+						parsed, err := parseRecurrence(input, now)
+						if err != nil {
+							// Keep the prompt open so the rule can be corrected
+							m.message = "Error: " + err.Error()
+							return m, nil
+						}
+						rule = parsed.String()
+						// Todos without a due date get the first occurrence from today on
+						first := parsed.first(now)
+						firstDue = &first
+					}
+					m.pushUndo()
+					list := *m.listFor(m.currentView)
+					list[idx].Recurrence = rule
+					if list[idx].DueAt == nil {
+						list[idx].DueAt = firstDue
+					}
+					if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+						return m, cmd
+					}
+					if rule == "" {
+						m.message = "Recurrence removed"
+					} else {
+						m.message = "Repeats " + rule
+					}
+				}
+				m.editingRecurrence = false
+				m.newRecurrence = ""
+			case "esc":
+				m.editingRecurrence = false
+				m.newRecurrence = ""
+				m.message = "Cancelled"
+			default:
+				handleTextInput(msg.String(), &m.newRecurrence, &m.textInputCursor)
+			}
+			return m, nil
+		}
+
+		// Handle update deletion confirmation (check this BEFORE navigatingUpdates)
+		if m.confirmingDeleteUpdate {
+			switch msg.String() {
+			case "y":
+				// Delete the update
+				currentList := m.getCurrentList()
+				if len(currentList) > 0 && m.cursor < len(currentList) {
+					m.pushUndo()
+					idx := m.selectedIndex()
+					switch m.currentView {
 					case viewCompleted:
 						m.updateCompletedTodo(func(t *Todo) {
 							t.Updates = append(t.Updates[:m.updateCursor], t.Updates[m.updateCursor+1:]...)
@@ -233,6 +438,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if cmd := m.save(completedFile, m.completed); cmd != nil {
 							return m, cmd
 						}
+					default:
+						list := *m.listFor(m.currentView)
+						updates := list[idx].Updates
+						list[idx].Updates = append(updates[:m.updateCursor], updates[m.updateCursor+1:]...)
+						if cmd := m.save(columnOf(m.currentView).file, list); cmd != nil {
+							return m, cmd
+						}
 					}
 
 					// Adjust cursor if needed
@@ -258,43 +470,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.confirmingMerge {
+			switch msg.String() {
+			case "y":
+				m.confirmingMerge = false
+				m.mergeConflictCopies()
+			case "n", "esc":
+				m.confirmingMerge = false
+				m.message = "Left the conflicted copies alone; run 'todo merge' to merge them later"
+			}
+			return m, nil
+		}
+
 		// Handle todo deletion confirmation
 		if m.confirmingDelete {
 			switch msg.String() {
 			case "y":
 				// Proceed with deletion
+				if list := m.getCurrentList(); len(list) > 0 && m.cursor < len(list) {
+					m.pushUndo()
+				}
+				idx := m.selectedIndex()
 				switch m.currentView {
-				case viewBacklog:
-					if len(m.backlog) > 0 && m.cursor < len(m.backlog) {
-						m.backlog = append(m.backlog[:m.cursor], m.backlog[m.cursor+1:]...)
-						if m.cursor >= len(m.backlog) && m.cursor > 0 {
-							m.cursor--
-						}
-						if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-							return m, cmd
-						}
-						m.message = "Todo deleted"
-					}
-				case viewReady:
-					if len(m.ready) > 0 && m.cursor < len(m.ready) {
-						m.ready = append(m.ready[:m.cursor], m.ready[m.cursor+1:]...)
-						if m.cursor >= len(m.ready) && m.cursor > 0 {
-							m.cursor--
-						}
-						if cmd := m.save(readyFile, m.ready); cmd != nil {
-							return m, cmd
-						}
-						m.message = "Todo deleted"
-					}
 				case viewCompleted:
 					if len(m.displayedCompleted) > 0 && m.cursor < len(m.displayedCompleted) {
 						// Find and remove from the actual completed list
-						todoToDelete := m.displayedCompleted[m.cursor]
-						for i, todo := range m.completed {
-							if todo.Text == todoToDelete.Text && todo.CreatedAt.Equal(todoToDelete.CreatedAt) {
-								m.completed = append(m.completed[:i], m.completed[i+1:]...)
-								break
-							}
+						if i := findTodoIndex(m.completed, m.displayedCompleted[m.cursor]); i >= 0 {
+							m.completed = append(m.completed[:i], m.completed[i+1:]...)
 						}
 						m.updateDisplayedCompleted()
 						if m.cursor >= len(m.displayedCompleted) && m.cursor > 0 {
@@ -305,6 +507,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						m.message = "Todo deleted"
 					}
+				default:
+					if idx >= 0 {
+						list := m.listFor(m.currentView)
+						*list = append((*list)[:idx], (*list)[idx+1:]...)
+						m.clampCursor()
+						if cmd := m.save(columnOf(m.currentView).file, *list); cmd != nil {
+							return m, cmd
+						}
+						m.message = "Todo deleted"
+					}
 				}
 				m.confirmingDelete = false
 				return m, nil
@@ -316,9 +528,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+
+		// Keys past this point are actions, which may be rebound in the config file
+		key := translateKey(msg.String())
+
+		if m.readOnly && (mutatingKeys[key] || archivedLockedKeys[key]) {
+			m.message = m.readOnlyMessage()
+			return m, nil
+		}
+
+		if m.browsingArchives {
+			return m, m.handleArchiveKey(key)
+		}
+
+		// Todos paged in from backups can be read but not changed
+		if archivedLockedKeys[key] && m.selectedArchived() {
+			m.message = "Archived todos can't be changed"
+			return m, nil
+		}
+
 		// Handle update navigation mode
 		if m.navigatingUpdates {
-			switch msg.String() {
+			switch key {
 			case "j":
 				currentList := m.getCurrentList()
 				if len(currentList) > 0 && m.cursor < len(currentList) {
@@ -370,7 +601,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
+		// Handle checklist navigation mode
+		if m.navigatingChecklist {
+			return m, m.handleChecklistKey(key)
+		}
+
+		// Jump between search matches until the search is cleared
+		if m.searchActive {
+			switch translateSearchKey(msg.String()) {
+			case "n":
+				m.nextMatch(1)
+				return m, nil
+			case "N":
+				m.nextMatch(-1)
+				return m, nil
+			case "esc":
+				m.clearSearch()
+				m.message = "Search cleared"
+				return m, nil
+			}
+		}
+
+		switch key {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 
@@ -410,17 +662,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.navigatingUpdates = false
 			m.updateCursor = 0
 
+		case "ctrl+d", "ctrl+u":
+			// Move half a screen down or up
+			currentList := m.getCurrentList()
+			step := m.halfPage()
+			if key == "ctrl+u" {
+				step = -step
+			}
+			m.cursor += step
+			if m.cursor > len(currentList)-1 {
+				m.cursor = len(currentList) - 1
+			}
+			if m.cursor < 0 {
+				m.cursor = 0
+			}
+			m.message = ""
+			m.showingUpdate = false
+			m.navigatingUpdates = false
+			m.updateCursor = 0
+
 		case "J":
-			if m.currentView == viewBacklog && len(m.backlog) > 0 && m.cursor < len(m.backlog)-1 {
-				swapTodos(m.backlog, m.cursor, m.cursor+1)
-				if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-					return m, cmd
-				}
-				m.cursor++
-				m.message = "Todo moved down"
-			} else if m.currentView == viewReady && len(m.ready) > 0 && m.cursor < len(m.ready)-1 {
-				swapTodos(m.ready, m.cursor, m.cursor+1)
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
+			// Swap with the next visible todo, which may not be adjacent when filtered
+			visible := m.visibleIndices()
+			if m.sortMode != sortManual && m.currentView != viewCompleted {
+				m.message = sortedReorderMessage
+			} else if list := m.listFor(m.currentView); list != nil && len(visible) > 0 && m.cursor < len(visible)-1 {
+				m.pushUndo()
+				swapTodos(*list, visible[m.cursor], visible[m.cursor+1])
+				if cmd := m.save(columnOf(m.currentView).file, *list); cmd != nil {
 					return m, cmd
 				}
 				m.cursor++
@@ -428,16 +697,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "K":
-			if m.currentView == viewBacklog && len(m.backlog) > 0 && m.cursor > 0 {
-				swapTodos(m.backlog, m.cursor, m.cursor-1)
-				if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-					return m, cmd
-				}
-				m.cursor--
-				m.message = "Todo moved up"
-			} else if m.currentView == viewReady && len(m.ready) > 0 && m.cursor > 0 {
-				swapTodos(m.ready, m.cursor, m.cursor-1)
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
+			visible := m.visibleIndices()
+			if m.sortMode != sortManual && m.currentView != viewCompleted {
+				m.message = sortedReorderMessage
+			} else if list := m.listFor(m.currentView); list != nil && len(visible) > 0 && m.cursor > 0 && m.cursor < len(visible) {
+				m.pushUndo()
+				swapTodos(*list, visible[m.cursor], visible[m.cursor-1])
+				if cmd := m.save(columnOf(m.currentView).file, *list); cmd != nil {
 					return m, cmd
 				}
 				m.cursor--
@@ -445,67 +711,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "t":
-			if m.currentView == viewBacklog && len(m.backlog) > 0 && m.cursor > 0 {
+			idx := m.selectedIndex()
+			if m.sortMode != sortManual && m.currentView != viewCompleted {
+				m.message = sortedReorderMessage
+			} else if list := m.listFor(m.currentView); list != nil && idx >= 0 && m.cursor > 0 {
 				// Move current todo to the top
-				todo := m.backlog[m.cursor]
-				m.backlog = append(m.backlog[:m.cursor], m.backlog[m.cursor+1:]...)
-				m.backlog = append([]Todo{todo}, m.backlog...)
+				m.pushUndo()
+				todo := (*list)[idx]
+				*list = append((*list)[:idx], (*list)[idx+1:]...)
+				*list = append([]Todo{todo}, *list...)
 				m.cursor = 0
-				if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-					return m, cmd
-				}
-				m.message = "Todo moved to top"
-			} else if m.currentView == viewReady && len(m.ready) > 0 && m.cursor > 0 {
-				// Move current todo to the top
-				todo := m.ready[m.cursor]
-				m.ready = append(m.ready[:m.cursor], m.ready[m.cursor+1:]...)
-				m.ready = append([]Todo{todo}, m.ready...)
-				m.cursor = 0
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
+				if cmd := m.save(columnOf(m.currentView).file, *list); cmd != nil {
 					return m, cmd
 				}
 				m.message = "Todo moved to top"
 			}
 
 		case "h":
-			switch m.currentView {
-			case viewReady:
-				m.currentView = viewBacklog
-				m.cursor = 0
-				m.message = ""
-				m.showingUpdate = false
-				m.navigatingUpdates = false
-				m.updateCursor = 0
-			case viewCompleted:
-				m.currentView = viewReady
-				m.cursor = 0
-				m.message = ""
-				m.showingUpdate = false
-				m.navigatingUpdates = false
-				m.updateCursor = 0
+			if v, ok := adjacentView(m.currentView, -1); ok {
+				m.switchView(v)
 			}
 
 		case "l":
-			switch m.currentView {
-			case viewBacklog:
-				m.currentView = viewReady
-				m.cursor = 0
-				m.message = ""
-				m.showingUpdate = false
-				m.navigatingUpdates = false
-				m.updateCursor = 0
-			case viewReady:
-				m.currentView = viewCompleted
-				m.updateDisplayedCompleted()
-				m.cursor = 0
-				m.message = ""
-				m.showingUpdate = false
-				m.navigatingUpdates = false
-				m.updateCursor = 0
+			if v, ok := adjacentView(m.currentView, 1); ok {
+				m.switchView(v)
+			}
+
+		case "<", ">":
+			// Move the todo to the neighbouring column
+			delta := 1
+			if key == "<" {
+				delta = -1
+			}
+			if v, ok := adjacentView(m.currentView, delta); ok {
+				if cmd := m.moveSelected(v); cmd != nil {
+					return m, cmd
+				}
 			}
 
 		case "a":
-			if m.currentView == viewBacklog || m.currentView == viewReady {
+			if m.currentView != viewCompleted {
 				m.adding = true
 				m.addingToTop = false
 				m.newTodo = ""
@@ -514,7 +759,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "A":
-			if m.currentView == viewBacklog || m.currentView == viewReady {
+			if m.currentView != viewCompleted {
 				m.adding = true
 				m.addingToTop = true
 				m.newTodo = ""
@@ -538,7 +783,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					todo := currentList[m.cursor]
 					if m.updateCursor < len(todo.Updates) {
 						m.editingUpdate = true
-						m.newUpdate = todo.Updates[m.updateCursor]
+						m.newUpdate = todo.Updates[m.updateCursor].Text
 						m.textInputCursor = len([]rune(m.newUpdate))
 						m.message = ""
 					}
@@ -552,63 +797,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "x":
-			if m.currentView == viewReady && len(m.ready) > 0 && m.cursor < len(m.ready) {
-				todo := m.ready[m.cursor]
-				now := time.Now()
-				todo.CompletedAt = &now
-				m.ready = append(m.ready[:m.cursor], m.ready[m.cursor+1:]...)
-				m.completed = append(m.completed, todo)
-				m.updateDisplayedCompleted()
-				if m.cursor >= len(m.ready) && m.cursor > 0 {
-					m.cursor--
-				}
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
+			// Complete from ready or any later column before the done column
+			if m.currentView != viewBacklog && m.currentView != viewCompleted {
+				if cmd := m.moveSelected(viewCompleted); cmd != nil {
 					return m, cmd
 				}
-				if cmd := m.save(completedFile, m.completed); cmd != nil {
-					return m, cmd
-				}
-				m.message = "Todo completed!"
 			}
 
 		case "r":
-			if m.currentView == viewCompleted && len(m.displayedCompleted) > 0 && m.cursor < len(m.displayedCompleted) {
-				// Find and remove from the actual completed list
-				todoToUndo := m.displayedCompleted[m.cursor]
-				for i, todo := range m.completed {
-					if todo.Text == todoToUndo.Text && todo.CreatedAt.Equal(todoToUndo.CreatedAt) {
-						// Clear the completion timestamp
-						todoToUndo.CompletedAt = nil
-						m.completed = append(m.completed[:i], m.completed[i+1:]...)
-						m.ready = append(m.ready, todoToUndo)
-						break
-					}
-				}
-				m.updateDisplayedCompleted()
-				if m.cursor >= len(m.displayedCompleted) && m.cursor > 0 {
-					m.cursor--
-				}
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
-					return m, cmd
-				}
-				if cmd := m.save(completedFile, m.completed); cmd != nil {
+			if m.currentView != viewReady {
+				if cmd := m.moveSelected(viewReady); cmd != nil {
 					return m, cmd
 				}
-				m.message = "Todo moved back to ready!"
-			} else if m.currentView == viewBacklog && len(m.backlog) > 0 && m.cursor < len(m.backlog) {
-				todo := m.backlog[m.cursor]
-				m.backlog = append(m.backlog[:m.cursor], m.backlog[m.cursor+1:]...)
-				m.ready = append(m.ready, todo)
-				if m.cursor >= len(m.backlog) && m.cursor > 0 {
-					m.cursor--
-				}
-				if cmd := m.save(backlogFile, m.backlog); cmd != nil {
-					return m, cmd
-				}
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
-					return m, cmd
-				}
-				m.message = "Todo moved to ready!"
 			}
 
 		case "B":
@@ -618,8 +818,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if err != nil {
 					m.message = "Backup failed: " + err.Error()
 				} else {
-					// Clear the completed list
+					// Clear the completed list, remembering the archive so undo can remove it
+					m.pushUndo()
+					m.recordFileChange(backupFile, nil, m.completed)
 					m.completed = []Todo{}
+					m.forgetArchive()
 					m.updateDisplayedCompleted()
 					m.cursor = 0
 					if cmd := m.save(completedFile, m.completed); cmd != nil {
@@ -630,20 +833,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "b":
-			if m.currentView == viewReady && len(m.ready) > 0 && m.cursor < len(m.ready) {
-				todo := m.ready[m.cursor]
-				m.ready = append(m.ready[:m.cursor], m.ready[m.cursor+1:]...)
-				m.backlog = append([]Todo{todo}, m.backlog...)
-				if m.cursor >= len(m.ready) && m.cursor > 0 {
-					m.cursor--
-				}
-				if cmd := m.save(readyFile, m.ready); cmd != nil {
-					return m, cmd
-				}
-				if cmd := m.save(backlogFile, m.backlog); cmd != nil {
+			if m.currentView != viewBacklog && m.currentView != viewCompleted {
+				if cmd := m.moveSelected(viewBacklog); cmd != nil {
 					return m, cmd
 				}
-				m.message = "Todo moved to backlog!"
 			}
 
 		case "i":
@@ -676,10 +869,109 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.message = ""
 			}
 
+		case "T":
+			currentList := m.getCurrentList()
+			if len(currentList) > 0 && m.cursor < len(currentList) {
+				m.editingTags = true
+				m.newTags = strings.Join(currentList[m.cursor].Tags, ", ")
+				m.textInputCursor = len([]rune(m.newTags))
+				m.message = ""
+			}
+
+		case "f":
+			m.filteringTags = true
+			m.newTagFilter = strings.Join(m.tagFilter, ", ")
+			m.textInputCursor = len([]rune(m.newTagFilter))
+			m.message = ""
+
+		case "F":
+			if len(m.tagFilter) > 0 {
+				m.setTagFilter(nil)
+				m.message = "Filter cleared"
+			}
+
+		case "D":
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.editingDue = true
+				m.newDue = ""
+				if due := m.getCurrentList()[m.cursor].DueAt; due != nil {
+					m.newDue = due.Format("2006-01-02")
+				}
+				m.textInputCursor = len([]rune(m.newDue))
+				m.message = ""
+			}
+
+		case "R":
+			if idx := m.selectedIndex(); idx >= 0 {
+				m.editingRecurrence = true
+				m.newRecurrence = m.getCurrentList()[m.cursor].Recurrence
+				m.textInputCursor = len([]rune(m.newRecurrence))
+				m.message = ""
+			}
+
+		case "S":
+			if m.currentView != viewCompleted {
+				// Cycle manual -> due date -> priority -> manual
+				switch m.sortMode {
+				case sortManual:
+					m.sortMode = sortDue
+					m.message = "Sorted by due date (S to sort by priority)"
+				case sortDue:
+					m.sortMode = sortPriority
+					m.message = "Sorted by priority (S for manual order)"
+				default:
+					m.sortMode = sortManual
+					m.message = "Manual order"
+				}
+				m.cursor = 0
+				m.showingUpdate = false
+				m.navigatingUpdates = false
+				m.updateCursor = 0
+			}
+
+		case "+", "=":
+			if cmd := m.setSelectedPriority(raisePriority); cmd != nil {
+				return m, cmd
+			}
+
+		case "-":
+			if cmd := m.setSelectedPriority(lowerPriority); cmd != nil {
+				return m, cmd
+			}
+
+		case "ctrl+z", "U":
+			if cmd := m.undo(); cmd != nil {
+				return m, cmd
+			}
+
+		case "ctrl+r":
+			if cmd := m.redo(); cmd != nil {
+				return m, cmd
+			}
+
+		case "/":
+			m.startSearch()
+
 		case "?":
 			m.showingCommands = !m.showingCommands
 			m.message = ""
 
+		case "C":
+			m.startChecklist()
+
+		case "m":
+			if cmd := m.toggleTimer(); cmd != nil {
+				return m, cmd
+			}
+
+		case "v":
+			m.showingBoard = !m.showingBoard
+			m.animation = boardAnimation{}
+			m.message = "Tabbed view"
+			if m.showingBoard {
+				m.message = "Board view (v for tabs)"
+			}
+
 		case "g":
 			// Go to top
 			m.cursor = 0
@@ -721,12 +1013,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "[", "]":
+			if m.currentView == viewCompleted {
+				if key == "]" {
+					m.pageCompleted(1)
+				} else {
+					m.pageCompleted(-1)
+				}
+			}
+
+		case "@":
+			if m.currentView == viewCompleted {
+				m.jumpingToDate = true
+				m.newJumpDate = ""
+				m.textInputCursor = 0
+				m.message = ""
+			}
+
+		case "%":
+			// Toggle the stats view, counting backups as well as the Completed tab
+			m.showingStats = !m.showingStats
+			if m.showingStats {
+				m.stats = computeStats(loadAllCompletedTodos(), time.Now())
+			}
+			m.message = ""
+
+		case "O":
+			m.openArchives()
+
 		case "esc":
-			// Universal untoggle: hide all updates and exit pretty view
-			if m.showingUpdate || m.showingAllUpdates || m.showingPrettify {
+			// Universal untoggle: hide all updates and exit pretty and stats views
+			if m.showingUpdate || m.showingAllUpdates || m.showingPrettify || m.showingStats {
 				m.showingUpdate = false
 				m.showingAllUpdates = false
 				m.showingPrettify = false
+				m.showingStats = false
 				m.message = ""
 			}
 		}
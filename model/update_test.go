@@ -142,6 +142,8 @@ func TestUpdateAddingMode(t *testing.T) {
 }
 
 func TestUpdateAddingToTopMode(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -352,7 +354,7 @@ func TestUpdateToggleUpdates(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1"), CreatedAt: time.Now()},
 		},
 		cursor:        0,
 		showingUpdate: false,
@@ -494,6 +496,8 @@ func TestInit(t *testing.T) {
 }
 
 func TestUpdateMoveBacklogToReady(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -519,6 +523,8 @@ func TestUpdateMoveBacklogToReady(t *testing.T) {
 }
 
 func TestUpdateMarkComplete(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewReady,
 		backlog:     []Todo{},
@@ -548,6 +554,8 @@ func TestUpdateMarkComplete(t *testing.T) {
 }
 
 func TestUpdateUndoComplete(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	now := time.Now()
 	m := Model{
 		currentView: viewCompleted,
@@ -578,7 +586,60 @@ func TestUpdateUndoComplete(t *testing.T) {
 	}
 }
 
+func TestUpdateUndoCompleteDuplicateText(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	now := time.Now()
+	earlier := now.Add(-1 * time.Hour)
+	m := Model{
+		currentView: viewCompleted,
+		ready:       []Todo{},
+		completed: []Todo{
+			{ID: "older", Text: "Weekly report", CreatedAt: now, CompletedAt: &earlier},
+			{ID: "newer", Text: "Weekly report", CreatedAt: now, CompletedAt: &now},
+		},
+		cursor: 1,
+	}
+	m.updateDisplayedCompleted()
+
+	// Cursor 1 is the older completion; 'r' must move that one and keep its ID
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+
+	if len(m.ready) != 1 || m.ready[0].ID != "older" {
+		t.Fatalf("ready = %v, want the todo with ID 'older'", m.ready)
+	}
+	if len(m.completed) != 1 || m.completed[0].ID != "newer" {
+		t.Errorf("completed = %v, want the todo with ID 'newer'", m.completed)
+	}
+}
+
+func TestUpdateNewTodoHasID(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	m := Model{currentView: viewReady, adding: true, newTodo: "task"}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if len(m.ready) != 1 || m.ready[0].ID == "" {
+		t.Fatalf("ready = %v, want one todo with an ID", m.ready)
+	}
+
+	// The ID is preserved when moving between lists
+	id := m.ready[0].ID
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = updated.(Model)
+	if len(m.completed) != 1 || m.completed[0].ID != id {
+		t.Errorf("completed todo ID = %v, want %q", m.completed, id)
+	}
+}
+
 func TestUpdateReorderDown(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -604,6 +665,8 @@ func TestUpdateReorderDown(t *testing.T) {
 }
 
 func TestUpdateReorderUp(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -629,6 +692,8 @@ func TestUpdateReorderUp(t *testing.T) {
 }
 
 func TestUpdateConfirmDelete(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -658,6 +723,8 @@ func TestUpdateConfirmDelete(t *testing.T) {
 }
 
 func TestUpdateSaveNewTodo(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog:     []Todo{},
@@ -684,6 +751,8 @@ func TestUpdateSaveNewTodo(t *testing.T) {
 }
 
 func TestUpdateSaveUpdates(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -698,7 +767,7 @@ func TestUpdateSaveUpdates(t *testing.T) {
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	m = updated.(Model)
 
-	if len(m.backlog[0].Updates) != 1 || m.backlog[0].Updates[0] != "new update" {
+	if len(m.backlog[0].Updates) != 1 || m.backlog[0].Updates[0].Text != "new update" {
 		t.Errorf("backlog[0].Updates = %v, want ['new update']", m.backlog[0].Updates)
 	}
 	if m.editingUpdate {
@@ -710,6 +779,8 @@ func TestUpdateSaveUpdates(t *testing.T) {
 }
 
 func TestUpdateSaveRename(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -736,6 +807,8 @@ func TestUpdateSaveRename(t *testing.T) {
 }
 
 func TestUpdateMoveReadyToBacklog(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewReady,
 		backlog: []Todo{
@@ -850,7 +923,7 @@ func TestUpdateEnterUpdatesNavigation(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1", "desc2"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1", "desc2"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: false,
@@ -896,7 +969,7 @@ func TestUpdatesNavigationMode(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1", "desc2", "desc3"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1", "desc2", "desc3"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: true,
@@ -961,7 +1034,7 @@ func TestUpdatesNavigationCreateNewUpdate(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1", "desc2"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1", "desc2"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: true,
@@ -989,8 +1062,8 @@ func TestUpdatesNavigationExitOnTodoChange(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1"}, CreatedAt: time.Now()},
-			{Text: "task2", Updates: []string{"desc2"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1"), CreatedAt: time.Now()},
+			{Text: "task2", Updates: textUpdates("desc2"), CreatedAt: time.Now()},
 		},
 		cursor:            1,
 		navigatingUpdates: false,
@@ -1021,10 +1094,12 @@ func TestUpdatesNavigationExitOnTodoChange(t *testing.T) {
 }
 
 func TestUpdatesDeletionInNavigation(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1", "desc2", "desc3"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1", "desc2", "desc3"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: true,
@@ -1050,7 +1125,7 @@ func TestUpdatesDeletionInNavigation(t *testing.T) {
 		t.Errorf("Updates count after deletion = %d, want 2", len(m.backlog[0].Updates))
 	}
 	// Verify correct update was deleted (desc2 at index 1)
-	if m.backlog[0].Updates[0] != "desc1" || m.backlog[0].Updates[1] != "desc3" {
+	if m.backlog[0].Updates[0].Text != "desc1" || m.backlog[0].Updates[1].Text != "desc3" {
 		t.Errorf("Wrong update deleted, got %v", m.backlog[0].Updates)
 	}
 }
@@ -1059,7 +1134,7 @@ func TestUpdatesDeletionCancelled(t *testing.T) {
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"desc1", "desc2"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("desc1", "desc2"), CreatedAt: time.Now()},
 		},
 		cursor:                 0,
 		navigatingUpdates:      true,
@@ -1080,10 +1155,12 @@ func TestUpdatesDeletionCancelled(t *testing.T) {
 }
 
 func TestUpdatesDeletionExitNavigationWhenEmpty(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"only desc"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("only desc"), CreatedAt: time.Now()},
 		},
 		cursor:                 0,
 		navigatingUpdates:      true,
@@ -1105,10 +1182,12 @@ func TestUpdatesDeletionExitNavigationWhenEmpty(t *testing.T) {
 }
 
 func TestEditUpdatesInNavigationMode(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"old desc 1", "old desc 2"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("old desc 1", "old desc 2"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: true,
@@ -1141,19 +1220,21 @@ func TestEditUpdatesInNavigationMode(t *testing.T) {
 	if len(m.backlog[0].Updates) != 2 {
 		t.Errorf("Updates count should remain 2, got %d", len(m.backlog[0].Updates))
 	}
-	if m.backlog[0].Updates[1] != "updated desc" {
-		t.Errorf("Updates[1] should be 'updated desc', got %q", m.backlog[0].Updates[1])
+	if m.backlog[0].Updates[1].Text != "updated desc" {
+		t.Errorf("Updates[1].Text should be 'updated desc', got %q", m.backlog[0].Updates[1].Text)
 	}
-	if m.backlog[0].Updates[0] != "old desc 1" {
-		t.Errorf("Updates[0] should be unchanged, got %q", m.backlog[0].Updates[0])
+	if m.backlog[0].Updates[0].Text != "old desc 1" {
+		t.Errorf("Updates[0].Text should be unchanged, got %q", m.backlog[0].Updates[0].Text)
 	}
 }
 
 func TestEditUpdatesNotInNavigationMode(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
-			{Text: "task1", Updates: []string{"existing"}, CreatedAt: time.Now()},
+			{Text: "task1", Updates: textUpdates("existing"), CreatedAt: time.Now()},
 		},
 		cursor:            0,
 		navigatingUpdates: false,
@@ -1182,15 +1263,17 @@ func TestEditUpdatesNotInNavigationMode(t *testing.T) {
 	if len(m.backlog[0].Updates) != 2 {
 		t.Errorf("Updates count should be 2, got %d", len(m.backlog[0].Updates))
 	}
-	if m.backlog[0].Updates[0] != "new desc" {
-		t.Errorf("Updates[0] should be 'new desc', got %q", m.backlog[0].Updates[0])
+	if m.backlog[0].Updates[0].Text != "new desc" {
+		t.Errorf("Updates[0].Text should be 'new desc', got %q", m.backlog[0].Updates[0].Text)
 	}
-	if m.backlog[0].Updates[1] != "existing" {
-		t.Errorf("Updates[1] should be 'existing', got %q", m.backlog[0].Updates[1])
+	if m.backlog[0].Updates[1].Text != "existing" {
+		t.Errorf("Updates[1].Text should be 'existing', got %q", m.backlog[0].Updates[1].Text)
 	}
 }
 
 func TestUpdateMoveToTopBacklog(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewBacklog,
 		backlog: []Todo{
@@ -1223,6 +1306,8 @@ func TestUpdateMoveToTopBacklog(t *testing.T) {
 }
 
 func TestUpdateMoveToTopReady(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	m := Model{
 		currentView: viewReady,
 		ready: []Todo{
@@ -1319,3 +1404,47 @@ func TestUpdateMoveToTopSingleItem(t *testing.T) {
 		t.Errorf("backlog[0].Text should remain 'only task', got %q", m.backlog[0].Text)
 	}
 }
+
+func TestUpdateHalfPageMovement(t *testing.T) {
+	var todos []Todo
+	for i := 0; i < 30; i++ {
+		todos = append(todos, Todo{Text: "task", CreatedAt: time.Now()})
+	}
+	m := Model{currentView: viewBacklog, backlog: todos, height: 20}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	if m.cursor != 10 {
+		t.Errorf("cursor after ctrl+d = %d, want 10", m.cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	if m.cursor != 29 {
+		t.Errorf("cursor after ctrl+d past end = %d, want 29", m.cursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = updated.(Model)
+	if m.cursor != 19 {
+		t.Errorf("cursor after ctrl+u = %d, want 19", m.cursor)
+	}
+
+	for i := 0; i < 3; i++ {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+		m = updated.(Model)
+	}
+	if m.cursor != 0 {
+		t.Errorf("cursor after ctrl+u past start = %d, want 0", m.cursor)
+	}
+
+	// Empty lists stay at 0
+	m = Model{currentView: viewBacklog, height: 20}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	if m.cursor != 0 {
+		t.Errorf("cursor after ctrl+d on empty list = %d, want 0", m.cursor)
+	}
+}
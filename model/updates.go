@@ -0,0 +1,63 @@
+package model
+
+import (
+	"os"
+	"time"
+)
+
+// updateStampLayout is how update times are shown in the UI
+const updateStampLayout = "Jan 2, 15:04"
+
+// updateAuthor returns the name recorded on new updates: the config file's author if
+// set (which may be empty to record none), otherwise $USER
+func updateAuthor() string {
+	if cfg.author != nil {
+		return *cfg.author
+	}
+	return os.Getenv("USER")
+}
+
+// newTodoUpdate returns an update written now by the current author
+func newTodoUpdate(text string, now time.Time) TodoUpdate {
+	return TodoUpdate{Text: text, CreatedAt: now, Author: updateAuthor()}
+}
+
+// editTodoUpdate changes an update's text, recording when it was last edited
+func editTodoUpdate(update *TodoUpdate, text string, now time.Time) {
+	if update.Text == text {
+		return
+	}
+	update.Text = text
+	update.EditedAt = &now
+}
+
+// formatUpdateStamp describes when and by whom an update was written, such as
+// "Jan 2, 15:04 by alice, edited Jan 3, 09:30" with the default layout, or "" for an
+// update saved before updates were timestamped
+func formatUpdateStamp(update TodoUpdate, layout string) string {
+	stamp := ""
+	if !update.CreatedAt.IsZero() {
+		stamp = update.CreatedAt.Format(layout)
+	}
+	if update.Author != "" {
+		if stamp != "" {
+			stamp += " "
+		}
+		stamp += "by " + update.Author
+	}
+	if update.EditedAt != nil {
+		if stamp != "" {
+			stamp += ", "
+		}
+		stamp += "edited " + update.EditedAt.Format(layout)
+	}
+	return stamp
+}
+
+// renderUpdateStamp renders the update's stamp to follow its text, or "" if it has none
+func renderUpdateStamp(update TodoUpdate) string {
+	if stamp := formatUpdateStamp(update, updateStampLayout); stamp != "" {
+		return " " + timestampStyle.Render("["+stamp+"]")
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// textUpdates returns untimestamped updates with the given texts, like those saved
+// before updates were structured
+func textUpdates(texts ...string) []TodoUpdate {
+	var updates []TodoUpdate
+	for _, text := range texts {
+		updates = append(updates, TodoUpdate{Text: text})
+	}
+	return updates
+}
+
+func TestTodoUnmarshalStructuredUpdates(t *testing.T) {
+	data := `{"text":"Task","created_at":"2024-01-01T10:00:00Z","updates":[` +
+		`{"text":"edited","created_at":"2024-01-02T09:00:00Z","edited_at":"2024-01-03T09:00:00Z","author":"sam"},` +
+		`"plain",5]}`
+	var todo Todo
+	if err := json.Unmarshal([]byte(data), &todo); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(todo.Updates) != 2 {
+		t.Fatalf("Updates = %+v, want the record and the plain string", todo.Updates)
+	}
+	first := todo.Updates[0]
+	if first.Text != "edited" || first.Author != "sam" || first.CreatedAt.Day() != 2 || first.EditedAt == nil || first.EditedAt.Day() != 3 {
+		t.Errorf("Updates[0] = %+v", first)
+	}
+	if todo.Updates[1] != (TodoUpdate{Text: "plain"}) {
+		t.Errorf("Updates[1] = %+v, want an untimestamped update", todo.Updates[1])
+	}
+
+	// Plain updates are saved back without a zero timestamp
+	saved, err := json.Marshal(todo.Updates[1])
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(saved) != `{"text":"plain"}` {
+		t.Errorf("Marshal() = %s", saved)
+	}
+}
+
+func TestFormatUpdateStamp(t *testing.T) {
+	created := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+	edited := time.Date(2026, 1, 3, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		update   TodoUpdate
+		expected string
+	}{
+		{"legacy", TodoUpdate{Text: "a"}, ""},
+		{"time only", TodoUpdate{Text: "a", CreatedAt: created}, "Jan 2, 15:04"},
+		{"with author", TodoUpdate{Text: "a", CreatedAt: created, Author: "sam"}, "Jan 2, 15:04 by sam"},
+		{"edited", TodoUpdate{Text: "a", CreatedAt: created, Author: "sam", EditedAt: &edited}, "Jan 2, 15:04 by sam, edited Jan 3, 09:30"},
+		{"edited legacy", TodoUpdate{Text: "a", EditedAt: &edited}, "edited Jan 3, 09:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatUpdateStamp(tt.update, updateStampLayout); got != tt.expected {
+				t.Errorf("formatUpdateStamp() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	defer func() { cfg = defaultConfig() }()
+	t.Setenv("USER", "sam")
+
+	if got := updateAuthor(); got != "sam" {
+		t.Errorf("updateAuthor() = %q, want $USER", got)
+	}
+	loaded, err := parseConfig([]byte(`{"author": "Sam Smith"}`))
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	cfg = loaded
+	if got := updateAuthor(); got != "Sam Smith" {
+		t.Errorf("updateAuthor() = %q, want the configured author", got)
+	}
+	cfg.author = new(string)
+	if got := updateAuthor(); got != "" {
+		t.Errorf("updateAuthor() = %q, want none when configured empty", got)
+	}
+}
+
+func TestUpdateStampsUpdates(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+	t.Setenv("USER", "sam")
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog:     []Todo{{Text: "task1", Updates: textUpdates("old"), CreatedAt: time.Now()}},
+	}
+
+	// New updates record when and by whom they were written
+	m.editingUpdate = true
+	m.newUpdate = "new"
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	added := m.backlog[0].Updates[0]
+	if added.Text != "new" || added.Author != "sam" || time.Since(added.CreatedAt) > time.Minute || added.EditedAt != nil {
+		t.Errorf("added update = %+v", added)
+	}
+
+	// Editing keeps the original time and records the edit
+	m.navigatingUpdates = true
+	m.updateCursor = 1
+	m.editingUpdate = true
+	m.newUpdate = "old, edited"
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	edited := m.backlog[0].Updates[1]
+	if edited.Text != "old, edited" || !edited.CreatedAt.IsZero() || edited.EditedAt == nil {
+		t.Errorf("edited update = %+v", edited)
+	}
+
+	m.showingAllUpdates = true
+	view := m.View()
+	if !strings.Contains(view, "by sam]") || !strings.Contains(view, "[edited ") {
+		t.Errorf("View should show update stamps:\n%s", view)
+	}
+}
@@ -2,8 +2,10 @@ package model
 
 import (
 	"fmt"
-	"log"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // renderColoredTextWithCursor renders text with a colored cursor at the specified position
@@ -123,7 +125,7 @@ func (m Model) renderPrettifyView(todos []Todo, title string, exitKey string) st
 	if availableWidth <= 0 {
 		availableWidth = 80
 	}
-	maxTextWidth := availableWidth - 35
+	maxTextWidth := availableWidth - cfg.widthMargin
 
 	// Render header
 	s.WriteString("  " + activeTabStyle.Render(title) + "\n\n")
@@ -145,6 +147,9 @@ func (m Model) renderPrettifyView(todos []Todo, title string, exitKey string) st
 			todoCount += len(day.Todos)
 		}
 		weekHeader := fmt.Sprintf("Week of %s (%d todos)", weekRange, todoCount)
+		if spent := weekTimeSpent(week, time.Now()); spent > 0 {
+			weekHeader = fmt.Sprintf("Week of %s (%d todos, %s logged)", weekRange, todoCount, formatDuration(spent))
+		}
 		s.WriteString("  " + headerStyle.Render(weekHeader) + "\n")
 		s.WriteString("  " + strings.Repeat("─", len(weekHeader)) + "\n\n")
 
@@ -152,13 +157,20 @@ func (m Model) renderPrettifyView(todos []Todo, title string, exitKey string) st
 		for _, day := range week.Days {
 			// Day header
 			dayHeader := formatDayHeader(day.Date)
-			s.WriteString("    " + countStyle.Render(dayHeader) + " " + helpTextStyle.Render(fmt.Sprintf("(%d todos)", len(day.Todos))) + "\n")
+			dayCount := fmt.Sprintf("(%d todos)", len(day.Todos))
+			if spent := timeSpentOn(day.Todos, time.Now()); spent > 0 {
+				dayCount = fmt.Sprintf("(%d todos, %s logged)", len(day.Todos), formatDuration(spent))
+			}
+			s.WriteString("    " + countStyle.Render(dayHeader) + " " + helpTextStyle.Render(dayCount) + "\n")
 
 			// Todos for this day
 			for _, todo := range day.Todos {
 				// Time of completion
 				timeStr := todo.CompletedAt.Format("15:04")
 				timestamp := timestampStyle.Render("[" + timeStr + "]")
+				if spent := renderTimeSpent(todo, time.Now()); spent != "" {
+					timestamp += " " + spent
+				}
 
 				// Wrap todo text if needed
 				wrappedLines := wrapText(todo.Text, maxTextWidth)
@@ -187,18 +199,31 @@ func (m Model) renderPrettifyView(todos []Todo, title string, exitKey string) st
 					}
 				}
 
+				for _, item := range todo.Checklist {
+					box := "☐ "
+					if item.Done {
+						box = "☑ "
+					}
+					s.WriteString("        " + helpTextStyle.Render(box+item.Text) + "\n")
+				}
+
 				// Always show all updates in prettify view
 				if len(todo.Updates) > 0 {
-					for _, updateText := range todo.Updates {
+					for _, update := range todo.Updates {
 						// Wrap update text
-						updateLines := wrapText(updateText, maxTextWidth-5)
+						updateLines := wrapText(update.Text, maxTextWidth-5)
+						stamp := renderUpdateStamp(update)
 
 						for j, updateLine := range updateLines {
 							if j == 0 {
-								s.WriteString("        " + updateStyle.Render("└─ "+updateLine) + "\n")
+								updateLine = updateStyle.Render("└─ " + updateLine)
 							} else {
-								s.WriteString("        " + updateStyle.Render("   "+updateLine) + "\n")
+								updateLine = updateStyle.Render("   " + updateLine)
+							}
+							if j == len(updateLines)-1 {
+								updateLine += stamp
 							}
+							s.WriteString("        " + updateLine + "\n")
 						}
 					}
 				}
@@ -220,11 +245,159 @@ func (m Model) renderPrettifyView(todos []Todo, title string, exitKey string) st
 	return s.String()
 }
 
+// renderTodo renders a single list item at index i, including any expanded complete
+// note and updates
+func (m Model) renderTodo(i int, todo Todo, maxTextWidth int) string {
+	s := strings.Builder{}
+
+	cursor := " "
+	if i == m.cursor {
+		cursor = cursorStyle.Render(">")
+	}
+
+	// Add update and complete note indicator
+	indicator := ""
+	if todo.CompleteNote != "" {
+		indicator = " ✓"
+	}
+	if len(todo.Updates) > 0 {
+		indicator += fmt.Sprintf(" 📄×%d", len(todo.Updates))
+	}
+	if progress := renderChecklistProgress(todo); progress != "" {
+		indicator += " " + progress
+	}
+	if spent := renderTimeSpent(todo, time.Now()); spent != "" {
+		indicator += " " + spent
+	}
+
+	if todo.Recurrence != "" {
+		indicator += " " + dueStyle.Render("↻ "+todo.Recurrence)
+	}
+
+	// Tags that aren't written as #hashtags in the text are listed after it
+	if extra := extraTags(todo); len(extra) > 0 {
+		indicator += " " + tagStyle.Render(formatTags(extra))
+	}
+
+	// Highlight search matches, flagging todos that only match in their details
+	renderText := func(line string) string { return renderTextWithTags(line, todo.Tags) }
+	if m.isSearchHighlighted(todo) {
+		renderText = func(line string) string { return highlightQuery(line, m.searchQuery) }
+		if !todoMatchesQuery(todo, m.searchQuery, false) {
+			indicator += " " + searchMatchStyle.Render("match in details")
+		}
+	}
+
+	// Wrap todo text if needed
+	wrappedLines := wrapText(todo.Text, maxTextWidth)
+
+	// Format the display based on view
+	var timestamp string
+	if m.currentView == viewCompleted && todo.CompletedAt != nil {
+		completedTime := todo.CompletedAt.Format("Jan 2, 15:04")
+		timestamp = timestampStyle.Render("[" + completedTime + "]")
+	} else {
+		createdTime := todo.CreatedAt.Format("Jan 2, 15:04")
+		timestamp = timestampStyle.Render("[" + createdTime + "]")
+	}
+	if due := renderDueDate(todo, time.Now()); due != "" {
+		timestamp += " " + due
+	}
+	if m.isArchived(todo) {
+		timestamp += " " + helpTextStyle.Render("(archived)")
+	}
+
+	// Render first line with cursor and timestamp
+	if len(wrappedLines) > 0 {
+		firstLine := wrappedLines[0]
+		// Add indicator to the first line
+		if len(wrappedLines) == 1 {
+			firstLine += indicator
+		}
+		todoText := renderText(firstLine)
+		if badge := renderPriority(todo.Priority); badge != "" {
+			todoText = badge + " " + todoText
+		}
+		s.WriteString(fmt.Sprintf("  %s %s %s\n", cursor, todoText, timestamp))
+
+		// Render additional wrapped lines with proper indentation
+		for j := 1; j < len(wrappedLines); j++ {
+			line := wrappedLines[j]
+			// Add indicator to the last line
+			if j == len(wrappedLines)-1 {
+				line += indicator
+			}
+			todoText := renderText(line)
+			s.WriteString(fmt.Sprintf("     %s\n", todoText))
+		}
+	}
+
+	// Show complete note and updates if toggled
+	shouldShowDetails := (m.showingUpdate && i == m.cursor) || m.showingAllUpdates
+	hasCompleteNote := todo.CompleteNote != ""
+	hasUpdates := len(todo.Updates) > 0
+
+	// Show complete note at top if it exists
+	if hasCompleteNote && shouldShowDetails {
+		noteLines := wrapText(todo.CompleteNote, maxTextWidth-5)
+		for j, noteLine := range noteLines {
+			if j == 0 {
+				s.WriteString("     " + "  " + completeNoteStyle.Render("✓ "+noteLine) + "\n")
+			} else {
+				s.WriteString("     " + "  " + completeNoteStyle.Render("  "+noteLine) + "\n")
+			}
+		}
+	}
+
+	// Show the checklist below the complete note, and always while it's being navigated
+	if len(todo.Checklist) > 0 && (shouldShowDetails || (m.navigatingChecklist && i == m.cursor)) {
+		s.WriteString(m.renderChecklist(todo, i == m.cursor, maxTextWidth))
+	}
+
+	// Show updates if toggled and cursor is on this todo, or if showing all updates
+	if hasUpdates && shouldShowDetails {
+		for updateIdx, update := range todo.Updates {
+			// Wrap update text, with when it was written after the last line
+			updateLines := wrapText(update.Text, maxTextWidth-5)
+			stamp := renderUpdateStamp(update)
+
+			// Add cursor indicator if in navigation mode
+			updateCursorIndicator := ""
+			if m.navigatingUpdates && i == m.cursor && updateIdx == m.updateCursor {
+				updateCursorIndicator = cursorStyle.Render("►") + " "
+			} else {
+				updateCursorIndicator = "  "
+			}
+
+			for j, updateLine := range updateLines {
+				if j == 0 {
+					updateLine = updateCursorIndicator + updateStyle.Render("└─ "+updateLine)
+				} else {
+					updateLine = "   " + updateStyle.Render("   "+updateLine)
+				}
+				if j == len(updateLines)-1 {
+					updateLine += stamp
+				}
+				s.WriteString("     " + updateLine + "\n")
+			}
+		}
+	}
+
+	return s.String()
+}
+
 // View renders the model's UI
 func (m Model) View() string {
+	if m.showingStats {
+		return m.renderStatsView()
+	}
+	if m.browsingArchives {
+		return m.renderArchiveView()
+	}
+
 	// Check if we're in prettify mode (only available in Completed view)
 	if m.currentView == viewCompleted && m.showingPrettify {
-		return m.renderPrettifyView(m.completed, "COMPLETED", "p")
+		return m.renderPrettifyView(m.completed, strings.ToUpper(viewName(viewCompleted)), "p")
 	}
 
 	s := strings.Builder{}
@@ -238,127 +411,183 @@ func (m Model) View() string {
 		availableWidth = 80 // Default width if not set yet
 	}
 	// Reserve space for padding, cursor, etc. (roughly 10 chars per line)
-	maxTextWidth := availableWidth - 35 // Account for "  > ", timestamp, indicators
-
-	// Render view tabs with colors
-	backlogTab := "BACKLOG"
-	readyTab := "READY"
-	completedTab := "COMPLETED"
+	maxTextWidth := availableWidth - cfg.widthMargin // Account for "  > ", timestamp, indicators
+
+	// The board heads each column with its name, so tabs are only needed without it
+	board := m.showingBoard && m.boardFits()
+	if !board {
+		// Render a tab per workflow column with colors
+		for _, v := range columnOrder() {
+			tab := strings.ToUpper(viewName(v))
+			if v == m.currentView {
+				s.WriteString("  " + activeTabStyle.Render(tab))
+			} else {
+				s.WriteString("  " + inactiveTabStyle.Render(tab))
+			}
+		}
+	}
 
-	switch m.currentView {
-	case viewBacklog:
-		s.WriteString("  " + activeTabStyle.Render(backlogTab) + "  " + inactiveTabStyle.Render(readyTab) + "  " + inactiveTabStyle.Render(completedTab) + "\n\n")
-	case viewReady:
-		s.WriteString("  " + inactiveTabStyle.Render(backlogTab) + "  " + activeTabStyle.Render(readyTab) + "  " + inactiveTabStyle.Render(completedTab) + "\n\n")
-	case viewCompleted:
-		s.WriteString("  " + inactiveTabStyle.Render(backlogTab) + "  " + inactiveTabStyle.Render(readyTab) + "  " + activeTabStyle.Render(completedTab) + "\n\n")
+	// Name the list in use when running with --list
+	if activeList != "" {
+		s.WriteString("  " + helpTextStyle.Render("list: "+activeList))
+	}
+	if !board || activeList != "" {
+		s.WriteString("\n\n")
 	}
 
 	// Display count of todos completed today
 	completedToday := m.countCompletedToday()
-	s.WriteString("  " + headerStyle.Render("Completed today:") + " " + countStyle.Render(fmt.Sprintf("%d", completedToday)) + "\n\n")
+	header := "  " + headerStyle.Render("Completed today:") + " " + countStyle.Render(fmt.Sprintf("%d", completedToday))
+	if dueSoon, overdue := m.countDueSoon(); dueSoon > 0 {
+		header += "   " + headerStyle.Render("Due soon:") + " " + countStyle.Render(fmt.Sprintf("%d", dueSoon))
+		if overdue > 0 {
+			header += " " + overdueStyle.Render(fmt.Sprintf("(%d overdue)", overdue))
+		}
+	}
+	if todo, ok := m.runningTimer(); ok {
+		header += "   " + headerStyle.Render("Timing:") + " " + todoTextStyle.Render(todo.Text) + " " + renderTimeSpent(todo, time.Now())
+	}
+	s.WriteString(header + "\n\n")
+
+	if m.readOnly {
+		s.WriteString("  " + warningMessageStyle.Render("READ-ONLY") + " " + helpTextStyle.Render(fmt.Sprintf("another copy of the app (%s) is editing these todos; changes made there show up here", m.lockOwner)) + "\n\n")
+	}
+
+	if m.showingBoard && !board {
+		s.WriteString("  " + helpTextStyle.Render("The board needs a wider terminal; showing tabs (v to turn off the board)") + "\n\n")
+	}
+
+	if m.currentView != viewCompleted {
+		switch m.sortMode {
+		case sortDue:
+			s.WriteString("  " + helpTextStyle.Render("Sorted by due date (S to sort by priority)") + "\n\n")
+		case sortPriority:
+			s.WriteString("  " + helpTextStyle.Render("Sorted by priority (S for manual order)") + "\n\n")
+		}
+	}
+
+	if len(m.tagFilter) > 0 {
+		s.WriteString("  " + headerStyle.Render("Filter:") + " " + tagStyle.Render(formatTags(m.tagFilter)) + " " + helpTextStyle.Render("(F to clear)") + "\n\n")
+	}
+
+	if m.currentView == viewCompleted && !board {
+		if pages := m.completedRange(); pages != "" {
+			s.WriteString("  " + helpTextStyle.Render(pages+" ([/] for newer/older, @ to jump to a date)") + "\n\n")
+		}
+	}
+
+	footer := m.renderFooter(maxTextWidth)
+	maxRows := m.height - strings.Count(s.String(), "\n") - strings.Count(footer, "\n") - 1
+
+	if board {
+		// Leave a row for the lane moved todos slide along
+		s.WriteString(m.renderBoard(maxRows - 1))
+		s.WriteString("\n")
+		s.WriteString(footer)
+		return s.String()
+	}
 
 	currentList := m.getCurrentList()
 
+	listContent := ""
 	if len(currentList) == 0 {
-		s.WriteString("  " + infoMessageStyle.Render("No todos") + "\n")
+		listContent = "  " + infoMessageStyle.Render("No todos") + "\n"
 	} else {
+		// Render each todo separately so the list can be windowed to the terminal height
+		items := make([]string, len(currentList))
 		for i, todo := range currentList {
-			cursor := " "
-			if i == m.cursor {
-				cursor = cursorStyle.Render(">")
-			}
-
-			// Add update and complete note indicator
-			indicator := ""
-			if todo.CompleteNote != "" {
-				indicator = " ✓"
-			}
-			if len(todo.Updates) > 0 {
-				indicator += fmt.Sprintf(" 📄×%d", len(todo.Updates))
-			}
+			items[i] = m.renderTodo(i, todo, maxTextWidth)
+		}
+		listContent = m.windowList(items, maxRows)
+	}
+	s.WriteString(listContent)
+	s.WriteString("\n")
+	s.WriteString(footer)
 
-			// Wrap todo text if needed
-			wrappedLines := wrapText(todo.Text, maxTextWidth)
+	return s.String()
+}
 
-			// Format the display based on view
-			var timestamp string
-			if m.currentView == viewCompleted && todo.CompletedAt != nil {
-				completedTime := todo.CompletedAt.Format("Jan 2, 15:04")
-				timestamp = timestampStyle.Render("[" + completedTime + "]")
-			} else {
-				createdTime := todo.CreatedAt.Format("Jan 2, 15:04")
-				timestamp = timestampStyle.Render("[" + createdTime + "]")
-			}
+// windowList joins rendered todo items, keeping only as many as fit in maxRows lines
+// around the cursor and noting how many are scrolled out of view above and below.
+// Every item is shown while the terminal height is still unknown.
+func (m Model) windowList(items []string, maxRows int) string {
+	if m.height <= 0 || len(items) == 0 {
+		return strings.Join(items, "")
+	}
 
-			// Render first line with cursor and timestamp
-			if len(wrappedLines) > 0 {
-				firstLine := wrappedLines[0]
-				// Add indicator to the first line
-				if len(wrappedLines) == 1 {
-					firstLine += indicator
-				}
-				todoText := todoTextStyle.Render(firstLine)
-				s.WriteString(fmt.Sprintf("  %s %s %s\n", cursor, todoText, timestamp))
-
-				// Render additional wrapped lines with proper indentation
-				for j := 1; j < len(wrappedLines); j++ {
-					line := wrappedLines[j]
-					// Add indicator to the last line
-					if j == len(wrappedLines)-1 {
-						line += indicator
-					}
-					todoText := todoTextStyle.Render(line)
-					s.WriteString(fmt.Sprintf("     %s\n", todoText))
-				}
-			}
+	heights := make([]int, len(items))
+	total := 0
+	for i, item := range items {
+		heights[i] = strings.Count(item, "\n")
+		total += heights[i]
+	}
+	if total <= maxRows {
+		return strings.Join(items, "")
+	}
 
-			// Show complete note and updates if toggled
-			shouldShowDetails := (m.showingUpdate && i == m.cursor) || m.showingAllUpdates
-			hasCompleteNote := todo.CompleteNote != ""
-			hasUpdates := len(todo.Updates) > 0
-
-			// Show complete note at top if it exists
-			if hasCompleteNote && shouldShowDetails {
-				noteLines := wrapText(todo.CompleteNote, maxTextWidth-5)
-				for j, noteLine := range noteLines {
-					if j == 0 {
-						s.WriteString("     " + "  " + completeNoteStyle.Render("✓ "+noteLine) + "\n")
-					} else {
-						s.WriteString("     " + "  " + completeNoteStyle.Render("  "+noteLine) + "\n")
-					}
-				}
-			}
+	// Reserve two rows for the scroll indicators
+	rows := maxRows - 2
+	if rows < 1 {
+		rows = 1
+	}
 
-			// Show updates if toggled and cursor is on this todo, or if showing all updates
-			if hasUpdates && shouldShowDetails {
-				for updateIdx, updateText := range todo.Updates {
-					// Wrap update text
-					updateLines := wrapText(updateText, maxTextWidth-5)
-
-					// Add cursor indicator if in navigation mode
-					updateCursorIndicator := ""
-					if m.navigatingUpdates && i == m.cursor && updateIdx == m.updateCursor {
-						updateCursorIndicator = cursorStyle.Render("►") + " "
-					} else {
-						updateCursorIndicator = "  "
-					}
+	cursor := m.cursor
+	if cursor >= len(items) {
+		cursor = len(items) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
 
-					for j, updateLine := range updateLines {
-						if j == 0 {
-							s.WriteString("     " + updateCursorIndicator + updateStyle.Render("└─ "+updateLine) + "\n")
-						} else {
-							s.WriteString("     " + "   " + updateStyle.Render("   "+updateLine) + "\n")
-						}
-					}
-				}
-			}
+	// Grow the window outwards from the cursor, alternating below and above so the
+	// cursor stays roughly centred
+	first, last := cursor, cursor
+	used := heights[cursor]
+	for {
+		grew := false
+		if last+1 < len(items) && used+heights[last+1] <= rows {
+			last++
+			used += heights[last]
+			grew = true
+		}
+		if first > 0 && used+heights[first-1] <= rows {
+			first--
+			used += heights[first]
+			grew = true
+		}
+		if !grew {
+			break
 		}
 	}
 
-	s.WriteString("\n")
+	s := strings.Builder{}
+	if first > 0 {
+		s.WriteString("  " + helpTextStyle.Render(fmt.Sprintf("↑ %d more above", first)) + "\n")
+	}
+	s.WriteString(strings.Join(items[first:last+1], ""))
+	if last < len(items)-1 {
+		s.WriteString("  " + helpTextStyle.Render(fmt.Sprintf("↓ %d more below", len(items)-1-last)) + "\n")
+	}
+	return s.String()
+}
+
+// renderFooter renders the input prompts, help and status message below the list
+func (m Model) renderFooter(maxTextWidth int) string {
+	s := strings.Builder{}
 
-	if m.adding {
+	if m.searching {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.searchQuery, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Search:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("          " + wrappedLines[i] + "\n")
+		}
+		scope := "text only"
+		if m.searchAll {
+			scope = "text, updates and notes"
+		}
+		s.WriteString("  " + helpTextStyle.Render(fmt.Sprintf("(searching %s in all lists and backups; Tab to toggle, Enter to confirm, Esc to cancel)", scope)) + "\n\n")
+	} else if m.adding {
 		// Wrap input text display if too wide
 		inputMaxWidth := maxTextWidth + 10 // Slightly more space for input
 		wrappedLines := renderWrappedTextWithCursor(m.newTodo, m.textInputCursor, inputMaxWidth)
@@ -376,6 +605,14 @@ func (m Model) View() string {
 			s.WriteString("                   " + wrappedLines[i] + "\n")
 		}
 		s.WriteString("  " + helpTextStyle.Render("(press Enter to save, Esc to cancel, arrows to navigate)") + "\n\n")
+	} else if m.editingChecklist {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newChecklistItem, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Checklist item:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("                  " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(press Enter to save, Esc to cancel, arrows to navigate)") + "\n\n")
 	} else if m.editingCompleteNote {
 		// Wrap input text display if too wide
 		inputMaxWidth := maxTextWidth + 10
@@ -394,44 +631,109 @@ func (m Model) View() string {
 			s.WriteString("              " + wrappedLines[i] + "\n")
 		}
 		s.WriteString("  " + helpTextStyle.Render("(press Enter to save, Esc to cancel, arrows to navigate)") + "\n\n")
+	} else if m.editingTags {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newTags, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Tags:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("        " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(separate tags with commas or spaces; Enter to save, Esc to cancel, clear to remove)") + "\n\n")
+	} else if m.filteringTags {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newTagFilter, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Filter by tags:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("                  " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(shows todos with any of these tags; Enter to apply, empty to clear, Esc to cancel)") + "\n\n")
+	} else if m.editingDue {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newDue, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Due date:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("            " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(today, tomorrow, fri, 2026-11-03 or +3d; Enter to save, Esc to cancel, clear to remove)") + "\n\n")
+	} else if m.jumpingToDate {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newJumpDate, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Jump to:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("           " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(2026-07-01, 2026-07, jul 2026, 2025 or 3w ago; Enter to jump, Esc to cancel)") + "\n\n")
+	} else if m.editingRecurrence {
+		inputMaxWidth := maxTextWidth + 10
+		wrappedLines := renderWrappedTextWithCursor(m.newRecurrence, m.textInputCursor, inputMaxWidth)
+		s.WriteString("  " + promptStyle.Render("Repeat:") + " " + wrappedLines[0] + "\n")
+		for i := 1; i < len(wrappedLines); i++ {
+			s.WriteString("          " + wrappedLines[i] + "\n")
+		}
+		s.WriteString("  " + helpTextStyle.Render("(daily, weekdays, weekly fri, monthly 15 or every 3 days; Enter to save, Esc to cancel, clear to stop repeating)") + "\n\n")
+	} else if m.confirmingMerge {
+		var copies []string
+		for _, conflict := range m.syncConflicts {
+			copies = append(copies, conflict.copies...)
+		}
+		s.WriteString("  " + errorMessageStyle.Render("A sync tool left conflicted copies of your todo files:") + "\n")
+		for _, path := range copies {
+			s.WriteString("    " + todoTextStyle.Render(path) + "\n")
+		}
+		s.WriteString("  " + errorMessageStyle.Render("Merge them into the todo files now? (y/n)") + "\n\n")
 	} else if m.confirmingDelete {
 		s.WriteString("  " + errorMessageStyle.Render("Are you sure you want to delete this todo? (y/n)") + "\n\n")
 	} else if m.confirmingDeleteUpdate {
 		s.WriteString("  " + errorMessageStyle.Render("Are you sure you want to delete this update? (y/n)") + "\n\n")
 	} else if m.showingCommands {
 		s.WriteString("  " + headerStyle.Render("Commands:") + "\n")
-		s.WriteString("  " + commandStyle.Render("j/k: move down/up  g/G: go to top/bottom  J/K: reorder (backlog/ready)  t: move to top (backlog/ready)  h/l: switch views") + "\n")
+		s.WriteString("  " + commandStyle.Render("j/k: move down/up  ctrl+d/ctrl+u: half page down/up  g/G: go to top/bottom  J/K: reorder  t: move to top  h/l: switch views  </>: move to previous/next column  v: toggle board") + "\n")
 		if m.currentView == viewCompleted {
-			s.WriteString("  " + commandStyle.Render("d: delete  r: move back to ready  p: prettify view  P: export markdown  B: backup and clear") + "\n")
+			s.WriteString("  " + commandStyle.Render("d: delete  r: move back to ready  [/]: newer/older page  @: jump to date  p: prettify view  P: export markdown  B: backup and clear") + "\n")
 		} else if m.currentView == viewReady {
-			s.WriteString("  " + commandStyle.Render("a: add  A: add to top  d: delete  x: mark complete  b: move to backlog") + "\n")
+			s.WriteString("  " + commandStyle.Render("a: add  A: add to top  d: delete  x: mark complete  b: move to backlog  D: due date  R: repeat  +/-: raise/lower priority  S: sort by due date/priority") + "\n")
 		} else if m.currentView == viewBacklog {
-			s.WriteString("  " + commandStyle.Render("a: add  A: add to top  d: delete  r: move to ready") + "\n")
+			s.WriteString("  " + commandStyle.Render("a: add  A: add to top  d: delete  r: move to ready  D: due date  R: repeat  +/-: raise/lower priority  S: sort by due date/priority") + "\n")
 		} else {
-			log.Fatalf("Invalid view: %v", m.currentView)
+			s.WriteString("  " + commandStyle.Render("a: add  A: add to top  d: delete  x: mark complete  r: move to ready  b: move to backlog  D: due date  R: repeat  +/-: raise/lower priority  S: sort by due date/priority") + "\n")
 		}
-		s.WriteString("  " + commandStyle.Render("i: toggle updates  I: toggle all updates  u: add update  c: complete note  enter: navigate updates  n: rename todo / edit update  ?: toggle help  q: quit") + "\n\n")
+		s.WriteString("  " + commandStyle.Render("i: toggle updates  I: toggle all updates  u: add update  c: complete note  enter: navigate updates  n: rename todo / edit update  C: checklist  m: start/stop timer") + "\n")
+		s.WriteString("  " + commandStyle.Render("T: edit tags  f: filter by tags  F: clear filter") + "\n")
+		s.WriteString("  " + commandStyle.Render("/: search (n/N: next/previous match)  %: stats  O: archives  ctrl+z/U: undo  ctrl+r: redo  ?: toggle help  q: quit") + "\n")
+		if custom := customKeysHelp(); custom != "" {
+			s.WriteString("  " + commandStyle.Render("Custom keys: "+custom) + "\n")
+		}
+		s.WriteString("\n")
 	} else {
 		s.WriteString("  " + helpTextStyle.Render("Press ? for help") + "\n\n")
 	}
 
+	if m.fileWarning != "" {
+		s.WriteString("  " + warningMessageStyle.Render(m.fileWarning) + "\n")
+	}
+
 	if m.message != "" {
-		// Determine message style based on content
-		msgStyle := infoMessageStyle
-		if strings.Contains(strings.ToLower(m.message), "added") ||
-			strings.Contains(strings.ToLower(m.message), "completed") ||
-			strings.Contains(strings.ToLower(m.message), "moved") ||
-			strings.Contains(strings.ToLower(m.message), "updated") ||
-			strings.Contains(strings.ToLower(m.message), "renamed") ||
-			strings.Contains(strings.ToLower(m.message), "backed up") {
-			msgStyle = successMessageStyle
-		} else if strings.Contains(strings.ToLower(m.message), "cancelled") ||
-			strings.Contains(strings.ToLower(m.message), "failed") ||
-			strings.Contains(strings.ToLower(m.message), "error") {
-			msgStyle = errorMessageStyle
-		}
-		s.WriteString("  " + msgStyle.Render(m.message) + "\n")
+		s.WriteString("  " + messageStyle(m.message).Render(m.message) + "\n")
 	}
 
 	return s.String()
 }
+
+// messageStyle picks the style for a status message based on its content
+func messageStyle(message string) lipgloss.Style {
+	message = strings.ToLower(message)
+	if strings.Contains(message, "added") ||
+		strings.Contains(message, "completed") ||
+		strings.Contains(message, "moved") ||
+		strings.Contains(message, "merged") ||
+		strings.Contains(message, "updated") ||
+		strings.Contains(message, "renamed") ||
+		strings.Contains(message, "backed up") {
+		return successMessageStyle
+	} else if strings.Contains(message, "cancelled") ||
+		strings.Contains(message, "failed") ||
+		strings.Contains(message, "error") {
+		return errorMessageStyle
+	}
+	return infoMessageStyle
+}
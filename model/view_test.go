@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -11,7 +13,7 @@ func TestViewRendering(t *testing.T) {
 		currentView: viewBacklog,
 		backlog: []Todo{
 			{Text: "task1", CreatedAt: now},
-			{Text: "task2", Updates: []string{"has update"}, CreatedAt: now},
+			{Text: "task2", Updates: textUpdates("has update"), CreatedAt: now},
 		},
 		cursor: 0,
 	}
@@ -339,7 +341,7 @@ func TestRenderPrettifyView(t *testing.T) {
 			todos: []Todo{
 				{
 					Text:        "Task with notes",
-					Updates:     []string{"Note 1", "Note 2"},
+					Updates:     textUpdates("Note 1", "Note 2"),
 					CreatedAt:   now,
 					CompletedAt: &completedNow,
 				},
@@ -457,3 +459,82 @@ func TestRenderWrappedTextWithCursor(t *testing.T) {
 		})
 	}
 }
+
+func TestViewWindowsLongLists(t *testing.T) {
+	now := time.Now()
+	var todos []Todo
+	for i := 0; i < 50; i++ {
+		todos = append(todos, Todo{Text: fmt.Sprintf("task%02d", i), CreatedAt: now})
+	}
+
+	m := Model{
+		currentView: viewBacklog,
+		backlog:     todos,
+		cursor:      25,
+		width:       80,
+		height:      20,
+	}
+
+	view := m.View()
+	lines := strings.Count(view, "\n")
+	if lines > m.height {
+		t.Errorf("View has %d lines, want at most terminal height %d", lines, m.height)
+	}
+	if !contains(view, "task25") {
+		t.Error("View should contain the todo under the cursor")
+	}
+	if contains(view, "task00") || contains(view, "task49") {
+		t.Error("View should not contain todos far from the cursor")
+	}
+	if !contains(view, "more above") || !contains(view, "more below") {
+		t.Error("View should show scroll indicators in both directions")
+	}
+
+	// At the top there is nothing above
+	m.cursor = 0
+	view = m.View()
+	if !contains(view, "task00") {
+		t.Error("View should contain the first todo when the cursor is at the top")
+	}
+	if contains(view, "more above") {
+		t.Error("View should not show an 'above' indicator at the top")
+	}
+	if !contains(view, "more below") {
+		t.Error("View should show a 'below' indicator at the top")
+	}
+}
+
+func TestViewShowsEverythingWithoutHeight(t *testing.T) {
+	now := time.Now()
+	var todos []Todo
+	for i := 0; i < 50; i++ {
+		todos = append(todos, Todo{Text: fmt.Sprintf("task%02d", i), CreatedAt: now})
+	}
+	m := Model{currentView: viewBacklog, backlog: todos}
+
+	view := m.View()
+	if !contains(view, "task00") || !contains(view, "task49") {
+		t.Error("View should render every todo before the terminal height is known")
+	}
+	if contains(view, "more above") || contains(view, "more below") {
+		t.Error("View should not show scroll indicators before the terminal height is known")
+	}
+}
+
+func TestWindowListKeepsExpandedCursorVisible(t *testing.T) {
+	m := Model{cursor: 5, height: 10}
+	items := make([]string, 10)
+	for i := range items {
+		items[i] = fmt.Sprintf("item%d\n", i)
+	}
+	// The cursor item is taller than the window; it should still be shown
+	items[5] = strings.Repeat("tall\n", 12)
+
+	result := m.windowList(items, 6)
+	if !strings.Contains(result, "tall") {
+		t.Error("windowList() should always include the cursor item")
+	}
+	if !strings.Contains(result, "5 more above") || !strings.Contains(result, "4 more below") {
+		t.Errorf("windowList() = %q, want indicators for hidden items", result)
+	}
+}
@@ -0,0 +1,250 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the todo files are checked for changes made by other
+// programs, such as a sync client or a second copy of the app
+const watchInterval = 2 * time.Second
+
+// fileCheckMsg checks the todo files for outside changes
+type fileCheckMsg struct{}
+
+// watchFiles schedules the next check of the todo files
+func watchFiles() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg { return fileCheckMsg{} })
+}
+
+// fileStamp identifies a version of a file by its modification time and size
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the file's stamp, or the zero stamp if it doesn't exist
+func statFile(filename string) fileStamp {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+// fileState is what a todo file held when it was last loaded or saved
+type fileState struct {
+	stamp fileStamp
+	todos []Todo
+}
+
+// trackFile remembers that filename now holds todos, so later changes by other
+// programs can be told apart from this session's
+func (m *Model) trackFile(filename string, todos []Todo) {
+	if m.files == nil {
+		m.files = map[string]fileState{}
+	}
+	m.files[filename] = fileState{statFile(filename), cloneTodos(todos)}
+}
+
+// changedOnDisk reports whether filename was changed by another program since this
+// session last loaded or saved it
+func (m *Model) changedOnDisk(filename string) bool {
+	state, ok := m.files[filename]
+	return ok && statFile(filename) != state.stamp
+}
+
+// equalTodo reports whether two todos hold exactly the same data
+func equalTodo(a, b Todo) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aData) == string(bData)
+}
+
+// mergeTodos combines one copy of a file's todos (ours, such as this session's) with
+// another (theirs, such as another program's), both changed from base. Todos are
+// matched by ID: a change on only one side is kept, and todos added on either side
+// are kept in place. When both sides changed a todo its fields are merged (see
+// mergeTodoFields). When one side removed a todo the other changed, nothing is
+// dropped. Each conflict is described in the returned list.
+func mergeTodos(base, ours, theirs []Todo) ([]Todo, []string) {
+	baseByID, oursByID, theirsByID := todosByID(base), todosByID(ours), todosByID(theirs)
+	merged := make([]Todo, 0, len(ours))
+	var conflicts []string
+	for _, todo := range ours {
+		b, inBase := baseByID[todo.ID]
+		t, inTheirs := theirsByID[todo.ID]
+		switch {
+		case !inBase && !inTheirs:
+			// Added in this session
+			merged = append(merged, todo)
+		case !inBase:
+			// In both with no common version to tell which side changed it, so every
+			// field that differs is a conflict
+			fields, fieldConflicts := mergeTodoFields(nil, todo, t)
+			merged = append(merged, fields)
+			if len(fieldConflicts) > 0 {
+				conflicts = append(conflicts, fmt.Sprintf("%q has a different %s in each copy", todo.Text, strings.Join(fieldConflicts, " and ")))
+			}
+		case !inTheirs:
+			// Removed outside, unless it was changed here
+			if !equalTodo(todo, b) {
+				merged = append(merged, todo)
+				conflicts = append(conflicts, fmt.Sprintf("%q was removed in one copy and changed in the other", todo.Text))
+			}
+		case equalTodo(todo, b):
+			merged = append(merged, t)
+		case equalTodo(t, b):
+			merged = append(merged, todo)
+		default:
+			fields, fieldConflicts := mergeTodoFields(&b, todo, t)
+			merged = append(merged, fields)
+			if len(fieldConflicts) > 0 {
+				conflicts = append(conflicts, fmt.Sprintf("%q has a different %s in each copy", todo.Text, strings.Join(fieldConflicts, " and ")))
+			}
+		}
+	}
+
+	// Todos only in theirs were added outside, or removed here but changed outside.
+	// Each goes after the todo before it in theirs, or at the top.
+	for i, todo := range theirs {
+		if _, inOurs := oursByID[todo.ID]; inOurs {
+			continue
+		}
+		if b, inBase := baseByID[todo.ID]; inBase {
+			if equalTodo(todo, b) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%q was removed in one copy and changed in the other", todo.Text))
+		}
+		at := 0
+		if i > 0 {
+			if prev := findTodoIndex(merged, theirs[i-1]); prev >= 0 {
+				at = prev + 1
+			} else {
+				at = len(merged)
+			}
+		}
+		merged = append(merged[:at], append([]Todo{todo}, merged[at:]...)...)
+	}
+	return merged, conflicts
+}
+
+// todosByID indexes todos by their ID
+func todosByID(todos []Todo) map[string]Todo {
+	byID := make(map[string]Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+	return byID
+}
+
+// fileView returns the view whose column is saved in filename
+func fileView(filename string) (view, bool) {
+	for _, v := range columnOrder() {
+		if columnOf(v).file == filename {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// setColumnTodos replaces the todos of the column saved in filename
+func (m *Model) setColumnTodos(filename string, todos []Todo) {
+	v, ok := fileView(filename)
+	if !ok {
+		return
+	}
+	if list := m.listFor(v); list != nil {
+		*list = todos
+	} else {
+		m.completed = todos
+		m.updateDisplayedCompleted()
+	}
+	m.clampCursor()
+}
+
+// mergeFromDisk merges another program's changes to filename into todos, keeping the
+// result in its column, and takes what's on disk as the version later changes are
+// compared with. Undo history is dropped, since undoing past the merge would
+// overwrite the other program's changes. If the file can't be read nothing changes.
+func (m *Model) mergeFromDisk(filename string, todos []Todo) ([]Todo, []string, error) {
+	stamp := statFile(filename)
+	theirs, _, err := readTodos(filename)
+	if err != nil {
+		return todos, nil, err
+	}
+	merged, conflicts := mergeTodos(m.files[filename].todos, todos, theirs)
+	m.files[filename] = fileState{stamp, theirs}
+	m.setColumnTodos(filename, merged)
+	m.undoStack = nil
+	m.redoStack = nil
+	return merged, conflicts, nil
+}
+
+// warnChangedOnDisk tells the user filename was changed outside the app and merged
+func (m *Model) warnChangedOnDisk(filename string, conflicts []string) {
+	m.fileWarning = fmt.Sprintf("%s changed on disk; merged its changes", filename)
+	if len(conflicts) > 0 {
+		m.fileWarning += fmt.Sprintf(", keeping this session's version where both changed: %s", strings.Join(conflicts, "; "))
+	}
+}
+
+// promptOpen reports whether a prompt or mode is open that holds on to the selected
+// todo or the todos being searched, so reloading could change what it applies to
+func (m *Model) promptOpen() bool {
+	return m.adding || m.editingUpdate || m.editingCompleteNote || m.renamingTodo ||
+		m.confirmingDelete || m.confirmingDeleteUpdate || m.confirmingMerge || m.navigatingUpdates ||
+		m.editingTags || m.editingDue || m.editingRecurrence || m.navigatingChecklist ||
+		m.editingChecklist || m.searching || m.searchActive || m.jumpingToDate ||
+		m.browsingArchives || m.searchingArchive
+}
+
+// reloadChangedFiles merges outside changes to the todo files into the lists. It waits
+// while a prompt is open and checks again on the next tick.
+func (m *Model) reloadChangedFiles() tea.Cmd {
+	if m.promptOpen() {
+		return nil
+	}
+	for _, v := range columnOrder() {
+		filename := columnOf(v).file
+		if !m.changedOnDisk(filename) {
+			continue
+		}
+		todos := m.completed
+		if list := m.listFor(v); list != nil {
+			todos = *list
+		}
+		merged, conflicts, err := m.mergeFromDisk(filename, todos)
+		if err != nil {
+			m.fileWarning = fmt.Sprintf("%s changed on disk but couldn't be read: %v", filename, err)
+			continue
+		}
+		m.warnChangedOnDisk(filename, conflicts)
+		if !equalTodoLists(merged, m.files[filename].todos) {
+			// Write back what this session kept
+			if cmd := m.save(filename, merged); cmd != nil {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// equalTodoLists reports whether two lists hold the same todos in the same order
+func equalTodoLists(a, b []Todo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equalTodo(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
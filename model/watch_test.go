@@ -0,0 +1,168 @@
+package model
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMergeTodos(t *testing.T) {
+	todo := func(id, text string) Todo { return Todo{ID: id, Text: text} }
+	base := []Todo{todo("1", "One"), todo("2", "Two"), todo("3", "Three")}
+	texts := func(todos []Todo) string {
+		var parts []string
+		for _, todo := range todos {
+			parts = append(parts, todo.Text)
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	tests := []struct {
+		name      string
+		ours      []Todo
+		theirs    []Todo
+		expected  string
+		conflicts int
+	}{
+		{
+			name:     "unchanged here takes theirs",
+			ours:     base,
+			theirs:   []Todo{todo("1", "One"), todo("2", "Two edited"), todo("3", "Three")},
+			expected: "One, Two edited, Three",
+		},
+		{
+			name:     "edits on both sides to different todos",
+			ours:     []Todo{todo("1", "One mine"), todo("2", "Two"), todo("3", "Three")},
+			theirs:   []Todo{todo("1", "One"), todo("2", "Two"), todo("3", "Three theirs")},
+			expected: "One mine, Two, Three theirs",
+		},
+		{
+			name:     "additions on both sides keep their places",
+			ours:     []Todo{todo("1", "One"), todo("2", "Two"), todo("3", "Three"), todo("4", "Mine")},
+			theirs:   []Todo{todo("0", "Theirs first"), todo("1", "One"), todo("2", "Two"), todo("5", "Theirs after two"), todo("3", "Three")},
+			expected: "Theirs first, One, Two, Theirs after two, Three, Mine",
+		},
+		{
+			name:     "removals on either side",
+			ours:     []Todo{todo("1", "One"), todo("3", "Three")},
+			theirs:   []Todo{todo("1", "One"), todo("2", "Two")},
+			expected: "One",
+		},
+		{
+			name:      "both sides edit the same todo",
+			ours:      []Todo{todo("1", "One"), todo("2", "Two mine"), todo("3", "Three")},
+			theirs:    []Todo{todo("1", "One"), todo("2", "Two theirs"), todo("3", "Three")},
+			expected:  "One, Two mine, Three",
+			conflicts: 1,
+		},
+		{
+			name:      "removed outside but edited here",
+			ours:      []Todo{todo("1", "One"), todo("2", "Two mine"), todo("3", "Three")},
+			theirs:    []Todo{todo("1", "One"), todo("3", "Three")},
+			expected:  "One, Two mine, Three",
+			conflicts: 1,
+		},
+		{
+			name:      "in both copies without a common version",
+			ours:      []Todo{todo("1", "One"), todo("2", "Two"), todo("3", "Three"), todo("4", "Four mine")},
+			theirs:    []Todo{todo("1", "One"), todo("2", "Two"), todo("3", "Three"), todo("4", "Four theirs")},
+			expected:  "One, Two, Three, Four mine",
+			conflicts: 1,
+		},
+		{
+			name:      "removed here but edited outside",
+			ours:      []Todo{todo("1", "One"), todo("3", "Three")},
+			theirs:    []Todo{todo("1", "One"), todo("2", "Two theirs"), todo("3", "Three")},
+			expected:  "One, Two theirs, Three",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := mergeTodos(base, tt.ours, tt.theirs)
+			if got := texts(merged); got != tt.expected || len(conflicts) != tt.conflicts {
+				t.Errorf("mergeTodos() = %q with conflicts %q, want %q with %d", got, conflicts, tt.expected, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestSaveMergesOutsideChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Mine"}})
+	m := InitialModel()
+
+	// Another machine adds a todo through the sync folder
+	saveTodos(readyFile, []Todo{{ID: "1", Text: "Mine"}, {ID: "2", Text: "From the laptop"}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	saved := loadTodos(readyFile)
+	if len(saved) != 1 || saved[0].ID != "2" || len(m.completed) != 1 {
+		t.Fatalf("ready = %+v, want the outside addition kept when completing", saved)
+	}
+	if !strings.Contains(m.View(), "todo_ready.txt changed on disk") {
+		t.Error("View should warn that the file changed on disk")
+	}
+	if len(m.undoStack) != 0 {
+		t.Error("undo history should be dropped after merging outside changes")
+	}
+
+	// The warning goes with the next key press
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if updated.(Model).fileWarning != "" {
+		t.Error("the warning should clear on the next key press")
+	}
+}
+
+func TestReloadChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	saveTodos(backlogFile, []Todo{{ID: "1", Text: "Someday"}})
+	m := InitialModel()
+	check := func() tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(fileCheckMsg{})
+		m = updated.(Model)
+		return cmd
+	}
+
+	if cmd := check(); cmd == nil || m.fileWarning != "" {
+		t.Fatal("checking unchanged files should only schedule the next check")
+	}
+
+	saveTodos(backlogFile, []Todo{{ID: "1", Text: "Someday, renamed elsewhere"}})
+	m.adding = true
+	check()
+	if m.backlog[0].Text != "Someday" {
+		t.Error("reloading should wait while a prompt is open")
+	}
+
+	m.adding = false
+	m.searchActive = true
+	check()
+	if m.backlog[0].Text != "Someday" {
+		t.Error("reloading should wait while search results are shown")
+	}
+
+	m.searchActive = false
+	if cmd := check(); cmd == nil {
+		t.Error("the next check should be scheduled")
+	}
+	if m.backlog[0].Text != "Someday, renamed elsewhere" || !strings.Contains(m.fileWarning, "todo_backlog.txt changed on disk") {
+		t.Errorf("backlog = %+v, warning = %q, want the outside change loaded", m.backlog, m.fileWarning)
+	}
+	if m.changedOnDisk(backlogFile) {
+		t.Error("the reloaded file should be tracked again")
+	}
+}